@@ -2,20 +2,42 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"terraform-provider-flespi/internal/provider/resources/common"
 	"terraform-provider-flespi/internal/provider/resources/gateway"
 	"terraform-provider-flespi/internal/provider/resources/platform"
 	"terraform-provider-flespi/internal/provider/resources/storage"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/function"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/mixser/flespi-client"
 )
 
-var _ provider.Provider = &flespiProvider{}
+var (
+	_ provider.Provider              = &flespiProvider{}
+	_ provider.ProviderWithFunctions = &flespiProvider{}
+)
+
+const (
+	defaultEndpoint     = "https://flespi.io"
+	defaultMaxRetries   = 3
+	defaultRetryMaxWait = 30
+	requestTimeout      = 10 * time.Second
+)
 
 // flespiProvider defines the provider implementation.
 type flespiProvider struct {
@@ -24,7 +46,15 @@ type flespiProvider struct {
 
 // FlespiProviderModel describes the provider data model.
 type FlespiProviderModel struct {
-	Token types.String `tfsdk:"token"`
+	Token        types.String `tfsdk:"token"`
+	TokenFile    types.String `tfsdk:"token_file"`
+	Endpoint     types.String `tfsdk:"endpoint"`
+	AccountId    types.Int64  `tfsdk:"account_id"`
+	MaxRetries   types.Int64  `tfsdk:"max_retries"`
+	RetryMaxWait types.Int64  `tfsdk:"retry_max_wait"`
+
+	RequestsPerSecond types.Float64 `tfsdk:"requests_per_second"`
+	Burst             types.Int64   `tfsdk:"burst"`
 }
 
 func (p *flespiProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -36,10 +66,49 @@ func (p *flespiProvider) Schema(ctx context.Context, req provider.SchemaRequest,
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
 			"token": schema.StringAttribute{
-				MarkdownDescription: "Flespi token",
-				Optional:            false,
+				MarkdownDescription: "Flespi token. Can also be read from `token_file` or the `FLESPI_TOKEN` environment variable.",
+				Optional:            true,
 				Sensitive:           true,
-				Required:            true,
+			},
+			"token_file": schema.StringAttribute{
+				MarkdownDescription: "Path to a file containing the flespi token. Used when `token` is not set.",
+				Optional:            true,
+			},
+			"endpoint": schema.StringAttribute{
+				MarkdownDescription: "Base URL of the flespi REST API. Defaults to the `FLESPI_ENDPOINT` environment variable, or `" + defaultEndpoint + "` when it is not set.",
+				Optional:            true,
+			},
+			"account_id": schema.Int64Attribute{
+				MarkdownDescription: "Subaccount to act as (sent as the `x-flespi-cid` header). Becomes the default `account_id` of every resource and data source; a resource's own `account_id` still takes precedence.",
+				Optional:            true,
+			},
+			"max_retries": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("How many times a request is retried after a rate limit (`429`) or transient server error. Defaults to `%d`; `0` disables retries.", defaultMaxRetries),
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"retry_max_wait": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("Longest wait between two attempts, in seconds. Caps both the exponential backoff and the `Retry-After` header sent while the account is blocked. Defaults to `%d`.", defaultRetryMaxWait),
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"requests_per_second": schema.Float64Attribute{
+				MarkdownDescription: "Average number of API requests per second the provider may send, shared by all resources and data sources. Set it below the account's API limit when Terraform runs many operations in parallel. Unlimited when not set.",
+				Optional:            true,
+				Validators: []validator.Float64{
+					float64validator.AtLeast(0),
+				},
+			},
+			"burst": schema.Int64Attribute{
+				MarkdownDescription: "Number of requests that may be sent at once before `requests_per_second` applies. Defaults to `1`.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
 			},
 		},
 	}
@@ -54,6 +123,14 @@ func (p *flespiProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		return
 	}
 
+	if config.Endpoint.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("endpoint"),
+			"Unknown Flespi API Endpoint",
+			"The provider cannot create the Flespi API client as there is an unknown configuration value for the endpoint.",
+		)
+	}
+
 	if config.Token.IsUnknown() {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("token"),
@@ -62,25 +139,128 @@ func (p *flespiProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		)
 	}
 
+	if config.AccountId.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("account_id"),
+			"Unknown Flespi Account ID",
+			"The provider cannot create the Flespi API client as there is an unknown configuration value for the account_id.",
+		)
+	}
+
+	if config.MaxRetries.IsUnknown() || config.RetryMaxWait.IsUnknown() {
+		resp.Diagnostics.AddError(
+			"Unknown Flespi Retry Settings",
+			"The provider cannot create the Flespi API client as there is an unknown configuration value for max_retries or retry_max_wait.",
+		)
+	}
+
+	if config.RequestsPerSecond.IsUnknown() || config.Burst.IsUnknown() {
+		resp.Diagnostics.AddError(
+			"Unknown Flespi Rate Limit Settings",
+			"The provider cannot create the Flespi API client as there is an unknown configuration value for requests_per_second or burst.",
+		)
+	}
+
+	if config.TokenFile.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("token_file"),
+			"Unknown Flespi Token File",
+			"The provider cannot create the Flespi API client as there is an unknown configuration value for the token file.",
+		)
+	}
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	token := config.Token.ValueString()
+	token, tokenSource, err := resolveToken(config)
+
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("token_file"),
+			"Unable to Read Flespi Token File",
+			err.Error(),
+		)
+		return
+	}
 
 	if token == "" {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("token"),
 			"Missing Flespi Token",
-			"We cannot create API client without token",
+			"We cannot create API client without token. The following sources were checked:\n"+
+				"  - the provider \"token\" attribute\n"+
+				"  - the file referenced by the provider \"token_file\" attribute\n"+
+				"  - the FLESPI_TOKEN environment variable",
 		)
+		return
 	}
 
-	if resp.Diagnostics.HasError() {
+	ctx = tflog.MaskMessageStrings(ctx, token)
+	ctx = tflog.MaskAllFieldValuesStrings(ctx, token)
+
+	endpoint := defaultEndpoint
+
+	if value := os.Getenv("FLESPI_ENDPOINT"); value != "" {
+		endpoint = value
+	}
+
+	if !config.Endpoint.IsNull() {
+		endpoint = config.Endpoint.ValueString()
+	}
+
+	endpoint, err = normalizeEndpoint(endpoint)
+
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("endpoint"),
+			"Invalid Flespi API Endpoint",
+			err.Error(),
+		)
 		return
 	}
 
-	client, err := flespi.NewClient("https://flespi.io", token)
+	tflog.Debug(ctx, "Creating Flespi client", map[string]interface{}{
+		"endpoint":     endpoint,
+		"token_source": tokenSource,
+		"account_id":   config.AccountId.ValueInt64(),
+	})
+
+	maxRetries := int64(defaultMaxRetries)
+
+	if !config.MaxRetries.IsNull() {
+		maxRetries = config.MaxRetries.ValueInt64()
+	}
+
+	retryMaxWait := int64(defaultRetryMaxWait)
+
+	if !config.RetryMaxWait.IsNull() {
+		retryMaxWait = config.RetryMaxWait.ValueInt64()
+	}
+
+	// Every attempt has to start answering within requestTimeout. The overall
+	// client timeout leaves room for all attempts and the waits between them,
+	// and still ends a call whose response body stalls.
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = requestTimeout
+
+	clientTimeout := time.Duration(maxRetries+1)*requestTimeout + time.Duration(maxRetries*retryMaxWait)*time.Second
+
+	burst := int64(1)
+
+	if !config.Burst.IsNull() {
+		burst = config.Burst.ValueInt64()
+	}
+
+	// Every attempt, including retries, goes through the shared limiter.
+	limited := common.NewLimitTransport(transport, config.RequestsPerSecond.ValueFloat64(), int(burst))
+
+	httpClient := common.NewAccountHTTPClient(&http.Client{
+		Timeout:   clientTimeout,
+		Transport: common.NewRetryTransport(ctx, limited, int(maxRetries), time.Duration(retryMaxWait)*time.Second),
+	}, config.AccountId.ValueInt64())
+
+	client, err := flespi.NewClient(endpoint, token, flespi.WithHTTPClient(httpClient))
 
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -105,13 +285,104 @@ func (p *flespiProvider) Resources(ctx context.Context) []func() resource.Resour
 		gateway.NewDeviceResource,
 		gateway.NewChannelResource,
 		gateway.NewGeofenceResource,
+		gateway.NewGeofenceDeviceAssignmentResource,
+		gateway.NewGeofenceDeviceAssignmentsResource,
+		gateway.NewGeofenceSetResource,
 		gateway.NewStreamResource,
+		gateway.NewCalculatorResource,
+		gateway.NewCalculatorDeviceAssignmentResource,
+		gateway.NewCalculatorGroupAssignmentResource,
+		gateway.NewPluginResource,
+		gateway.NewPluginDeviceAssignmentResource,
+		gateway.NewPluginGroupAssignmentResource,
+		gateway.NewGroupResource,
+		gateway.NewStreamDeviceSubscriptionResource,
+		gateway.NewStreamDeviceSubscriptionsResource,
+		gateway.NewStreamChannelSubscriptionResource,
+		gateway.NewStreamGroupSubscriptionResource,
 		storage.NewCDNResource,
 	}
 }
 
 func (p *flespiProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
-	return []func() datasource.DataSource{}
+	return []func() datasource.DataSource{
+		platform.NewLimitDataSource,
+		platform.NewLimitsDataSource,
+		platform.NewSubaccountDataSource,
+		platform.NewSubaccountsDataSource,
+		platform.NewWebhookDataSource,
+		platform.NewWebhooksDataSource,
+		platform.NewTokenDataSource,
+		platform.NewTokensDataSource,
+		gateway.NewDeviceDataSource,
+		gateway.NewDevicesDataSource,
+		gateway.NewDeviceTypeDataSource,
+		gateway.NewChannelDataSource,
+		gateway.NewChannelsDataSource,
+		gateway.NewChannelProtocolDataSource,
+		gateway.NewChannelProtocolsDataSource,
+		gateway.NewGeofenceDataSource,
+		gateway.NewGeofencesDataSource,
+		gateway.NewStreamDataSource,
+		gateway.NewStreamsDataSource,
+		gateway.NewStreamProtocolDataSource,
+		gateway.NewStreamProtocolsDataSource,
+		storage.NewCDNDataSource,
+		storage.NewCDNsDataSource,
+	}
+}
+
+func (p *flespiProvider) Functions(ctx context.Context) []func() function.Function {
+	return []func() function.Function{
+		gateway.NewGeofenceFromGeoJSONFunction,
+		gateway.NewGeofenceFromKMLFunction,
+	}
+}
+
+// resolveToken returns the token and the name of the source it was taken from,
+// checking the token attribute, the token file and FLESPI_TOKEN in that order.
+func resolveToken(config FlespiProviderModel) (string, string, error) {
+	if token := config.Token.ValueString(); token != "" {
+		return token, "token", nil
+	}
+
+	if tokenFile := config.TokenFile.ValueString(); tokenFile != "" {
+		content, err := os.ReadFile(tokenFile)
+
+		if err != nil {
+			return "", "", fmt.Errorf("could not read token file %q: %w", tokenFile, err)
+		}
+
+		token := strings.TrimSpace(string(content))
+
+		if token == "" {
+			return "", "", fmt.Errorf("token file %q is empty", tokenFile)
+		}
+
+		return token, "token_file", nil
+	}
+
+	return os.Getenv("FLESPI_TOKEN"), "FLESPI_TOKEN", nil
+}
+
+// normalizeEndpoint checks that endpoint is an absolute http(s) URL and strips
+// the trailing slash, as the client joins it with the API path itself.
+func normalizeEndpoint(endpoint string) (string, error) {
+	parsed, err := url.Parse(endpoint)
+
+	if err != nil {
+		return "", fmt.Errorf("endpoint %q is not a valid URL: %w", endpoint, err)
+	}
+
+	if parsed.Scheme != "https" && parsed.Scheme != "http" {
+		return "", fmt.Errorf("endpoint %q must use the http or https scheme", endpoint)
+	}
+
+	if parsed.Host == "" {
+		return "", fmt.Errorf("endpoint %q must be an absolute URL including the host", endpoint)
+	}
+
+	return strings.TrimRight(endpoint, "/"), nil
 }
 
 func New(version string) func() provider.Provider {
@@ -0,0 +1,103 @@
+package common
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+
+	flespi "github.com/mixser/flespi-client"
+)
+
+const accountHeader = "x-flespi-cid"
+
+// accountTransport sends the x-flespi-cid header on every request that does not
+// already carry one, so all calls act on behalf of the configured subaccount.
+type accountTransport struct {
+	base      http.RoundTripper
+	accountId int64
+
+	scopedMu sync.Mutex
+	scoped   map[int64]*flespi.Client
+}
+
+func (t *accountTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	if t.accountId != 0 && request.Header.Get(accountHeader) == "" {
+		request = request.Clone(request.Context())
+		request.Header.Set(accountHeader, strconv.FormatInt(t.accountId, 10))
+	}
+
+	return t.base.RoundTrip(request)
+}
+
+// NewAccountHTTPClient returns an HTTP client which acts as accountId unless a
+// request sets x-flespi-cid itself. A zero accountId disables the header.
+func NewAccountHTTPClient(base *http.Client, accountId int64) *http.Client {
+	transport := base.Transport
+
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	return &http.Client{
+		Timeout: base.Timeout,
+		Transport: &accountTransport{
+			base:      transport,
+			accountId: accountId,
+		},
+	}
+}
+
+// ForAccount returns a client scoped to accountId, whether or not the provider
+// has a default account_id. When accountId is zero or matches the provider
+// default, the provider client is returned unchanged so requests behave
+// exactly as they would without scoping.
+func ForAccount(client *flespi.Client, accountId int64) *flespi.Client {
+	transport, ok := client.HTTPClient.Transport.(*accountTransport)
+
+	if !ok || accountId == 0 || accountId == transport.accountId {
+		return client
+	}
+
+	transport.scopedMu.Lock()
+	defer transport.scopedMu.Unlock()
+
+	if scoped, ok := transport.scoped[accountId]; ok {
+		return scoped
+	}
+
+	options := []flespi.ClientOption{
+		flespi.WithHTTPClient(&http.Client{
+			Timeout: client.HTTPClient.Timeout,
+			Transport: &accountTransport{
+				base:      transport.base,
+				accountId: accountId,
+			},
+		}),
+		flespi.WithRetryConfig(client.RetryConfig),
+	}
+
+	if client.Logger != nil {
+		options = append(options, flespi.WithLogger(client.Logger))
+	}
+
+	// NewClient never fails; it only assembles the sub-clients.
+	scoped, _ := flespi.NewClient(client.Host, client.Token, options...)
+
+	if transport.scoped == nil {
+		transport.scoped = make(map[int64]*flespi.Client)
+	}
+
+	transport.scoped[accountId] = scoped
+
+	return scoped
+}
+
+// AccountHeaders returns the headers that make a raw API request act as
+// accountId, or nil for the provider's own account.
+func AccountHeaders(accountId int64) map[string]string {
+	if accountId == 0 {
+		return nil
+	}
+
+	return map[string]string{accountHeader: strconv.FormatInt(accountId, 10)}
+}
@@ -0,0 +1,19 @@
+package common
+
+import (
+	"errors"
+
+	flespi "github.com/mixser/flespi-client"
+)
+
+// IsNotFound reports whether err is a flespi API "not found" response, which
+// means the object was removed outside of Terraform.
+func IsNotFound(err error) bool {
+	var apiErr *flespi.APIError
+
+	if errors.As(err, &apiErr) {
+		return flespi.IsNotFoundError(apiErr)
+	}
+
+	return false
+}
@@ -0,0 +1,111 @@
+package common
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// ListFilter holds the optional filters accepted by plural data sources. Null
+// values mean the corresponding filter is not applied.
+type ListFilter struct {
+	nameRegex *regexp.Regexp
+
+	AccountId     types.Int64
+	Enabled       types.Bool
+	MetadataKey   types.String
+	MetadataValue types.String
+}
+
+// NewListFilter compiles the name pattern and builds a ListFilter from data source configuration.
+func NewListFilter(nameRegex types.String, accountId types.Int64, enabled types.Bool, metadataKey types.String, metadataValue types.String) (ListFilter, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	filter := ListFilter{
+		AccountId:     accountId,
+		Enabled:       enabled,
+		MetadataKey:   metadataKey,
+		MetadataValue: metadataValue,
+	}
+
+	if !nameRegex.IsNull() && !nameRegex.IsUnknown() {
+		compiled, err := regexp.Compile(nameRegex.ValueString())
+
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("name_regex"),
+				"Invalid name_regex",
+				fmt.Sprintf("Unable to compile %q: %s", nameRegex.ValueString(), err),
+			)
+			return filter, diags
+		}
+
+		filter.nameRegex = compiled
+	}
+
+	if !metadataValue.IsNull() && metadataKey.IsNull() {
+		diags.AddAttributeError(
+			path.Root("metadata_value"),
+			"Missing metadata_key",
+			"metadata_value can only be used together with metadata_key.",
+		)
+	}
+
+	return filter, diags
+}
+
+func (f ListFilter) MatchName(name string) bool {
+	return f.nameRegex == nil || f.nameRegex.MatchString(name)
+}
+
+func (f ListFilter) MatchAccountId(accountId int64) bool {
+	return f.AccountId.IsNull() || f.AccountId.IsUnknown() || f.AccountId.ValueInt64() == accountId
+}
+
+func (f ListFilter) MatchEnabled(enabled bool) bool {
+	return f.Enabled.IsNull() || f.Enabled.IsUnknown() || f.Enabled.ValueBool() == enabled
+}
+
+func (f ListFilter) MatchMetadata(metadata map[string]string) bool {
+	if f.MetadataKey.IsNull() || f.MetadataKey.IsUnknown() {
+		return true
+	}
+
+	value, ok := metadata[f.MetadataKey.ValueString()]
+
+	if !ok {
+		return false
+	}
+
+	return f.MetadataValue.IsNull() || f.MetadataValue.IsUnknown() || f.MetadataValue.ValueString() == value
+}
+
+// FindByName returns the only item whose name exactly matches name. It is an
+// error when no item or more than one item matches.
+func FindByName[T any](items []T, name string, getName func(T) string, getId func(T) int64) (*T, error) {
+	var found []T
+
+	for _, item := range items {
+		if getName(item) == name {
+			found = append(found, item)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("no object named %q found", name)
+	case 1:
+		return &found[0], nil
+	default:
+		ids := make([]string, 0, len(found))
+		for _, item := range found {
+			ids = append(ids, fmt.Sprintf("%d", getId(item)))
+		}
+
+		return nil, fmt.Errorf("%d objects named %q found (ids: %s), look it up by id instead", len(found), name, strings.Join(ids, ", "))
+	}
+}
@@ -0,0 +1,76 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+)
+
+// ImportStateById imports a resource by its numeric flespi ID.
+func ImportStateById(ctx context.Context, request resource.ImportStateRequest, response *resource.ImportStateResponse) {
+	id, err := strconv.ParseInt(strings.TrimSpace(request.ID), 10, 64)
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Invalid Import ID",
+			fmt.Sprintf("Expected a numeric flespi ID, got: %q", request.ID),
+		)
+		return
+	}
+
+	response.Diagnostics.Append(response.State.SetAttribute(ctx, path.Root("id"), id)...)
+}
+
+// ImportStateByAccountScopedId imports a resource either by its numeric flespi ID
+// or by the composite "account_id/id" form used for objects living in subaccounts.
+func ImportStateByAccountScopedId(ctx context.Context, request resource.ImportStateRequest, response *resource.ImportStateResponse) {
+	accountId, id, err := ParseAccountScopedId(request.ID)
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Invalid Import ID",
+			fmt.Sprintf("Expected \"id\" or \"account_id/id\", got: %q (%s)", request.ID, err),
+		)
+		return
+	}
+
+	response.Diagnostics.Append(response.State.SetAttribute(ctx, path.Root("id"), id)...)
+
+	if accountId != 0 {
+		response.Diagnostics.Append(response.State.SetAttribute(ctx, path.Root("account_id"), accountId)...)
+	}
+}
+
+// ParseAccountScopedId splits an "account_id/id" or plain "id" string. A zero
+// account ID is returned for the plain form.
+func ParseAccountScopedId(value string) (int64, int64, error) {
+	parts := strings.Split(strings.TrimSpace(value), "/")
+
+	switch len(parts) {
+	case 1:
+		id, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid id: %w", err)
+		}
+
+		return 0, id, nil
+	case 2:
+		accountId, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid account_id: %w", err)
+		}
+
+		id, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid id: %w", err)
+		}
+
+		return accountId, id, nil
+	default:
+		return 0, 0, fmt.Errorf("too many segments")
+	}
+}
@@ -0,0 +1,73 @@
+package common
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+)
+
+// PreferConfiguredJSON returns the JSON to keep in state for a document that
+// flespi echoes back with defaults filled in. When remote holds everything in
+// configured with the same values, configured is kept so the added defaults
+// don't show up as a diff; otherwise remote is returned as real drift.
+func PreferConfiguredJSON(configured jsontypes.Normalized, remote string) jsontypes.Normalized {
+	if configured.IsNull() || configured.IsUnknown() {
+		return jsontypes.NewNormalizedValue(remote)
+	}
+
+	if JSONContains(remote, configured.ValueString()) {
+		return configured
+	}
+
+	return jsontypes.NewNormalizedValue(remote)
+}
+
+// JSONContains reports whether the JSON document remote holds every field of
+// the JSON document want with the same value. Invalid JSON contains nothing.
+func JSONContains(remote, want string) bool {
+	var wantValue, remoteValue interface{}
+
+	if json.Unmarshal([]byte(want), &wantValue) != nil || json.Unmarshal([]byte(remote), &remoteValue) != nil {
+		return false
+	}
+
+	return jsonContains(remoteValue, wantValue)
+}
+
+// jsonContains reports whether got has every field of want with an equal
+// value. Arrays must have the same length and match element by element.
+func jsonContains(got, want interface{}) bool {
+	switch wantTyped := want.(type) {
+	case map[string]interface{}:
+		gotTyped, ok := got.(map[string]interface{})
+
+		if !ok {
+			return false
+		}
+
+		for key, value := range wantTyped {
+			if !jsonContains(gotTyped[key], value) {
+				return false
+			}
+		}
+
+		return true
+	case []interface{}:
+		gotTyped, ok := got.([]interface{})
+
+		if !ok || len(gotTyped) != len(wantTyped) {
+			return false
+		}
+
+		for i := range wantTyped {
+			if !jsonContains(gotTyped[i], wantTyped[i]) {
+				return false
+			}
+		}
+
+		return true
+	default:
+		return reflect.DeepEqual(got, want)
+	}
+}
@@ -0,0 +1,193 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// ValidateJSONSchema checks value against the subset of JSON Schema used by the
+// flespi protocol catalog: type, properties, required, additionalProperties,
+// items and enum. Each problem is returned as a message prefixed with the JSON
+// path of the offending value. Keywords it does not know are ignored, so an
+// unfamiliar schema never rejects a configuration on its own.
+func ValidateJSONSchema(schema json.RawMessage, value interface{}) ([]string, error) {
+	if len(schema) == 0 || string(schema) == "null" {
+		return nil, nil
+	}
+
+	var parsed map[string]interface{}
+
+	if err := json.Unmarshal(schema, &parsed); err != nil {
+		return nil, fmt.Errorf("could not parse configuration schema: %w", err)
+	}
+
+	var problems []string
+
+	validateSchemaNode(parsed, value, "$", &problems)
+
+	return problems, nil
+}
+
+func validateSchemaNode(schema map[string]interface{}, value interface{}, location string, problems *[]string) {
+	if types := schemaTypes(schema["type"]); len(types) > 0 && !matchesAnyType(value, types) {
+		*problems = append(*problems, fmt.Sprintf("%s: expected %s, got %s", location, strings.Join(types, " or "), jsonTypeName(value)))
+		return
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok && !containsJSONValue(enum, value) {
+		*problems = append(*problems, fmt.Sprintf("%s: value %s is not one of the allowed values", location, encodeJSON(value)))
+	}
+
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		validateSchemaObject(schema, typed, location, problems)
+	case []interface{}:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range typed {
+				validateSchemaNode(items, item, fmt.Sprintf("%s[%d]", location, i), problems)
+			}
+		}
+	}
+}
+
+func validateSchemaObject(schema map[string]interface{}, value map[string]interface{}, location string, problems *[]string) {
+	properties, _ := schema["properties"].(map[string]interface{})
+
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, name := range required {
+			if key, ok := name.(string); ok {
+				if _, present := value[key]; !present {
+					*problems = append(*problems, fmt.Sprintf("%s: missing required field %q", location, key))
+				}
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(value))
+	for key := range value {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		child := location + "." + key
+
+		if property, ok := properties[key].(map[string]interface{}); ok {
+			validateSchemaNode(property, value[key], child, problems)
+			continue
+		}
+
+		switch additional := schema["additionalProperties"].(type) {
+		case map[string]interface{}:
+			validateSchemaNode(additional, value[key], child, problems)
+		case bool:
+			// As in JSON Schema, only an explicit false closes the object:
+			// without additionalProperties unlisted fields are accepted.
+			if !additional {
+				*problems = append(*problems, fmt.Sprintf("%s: unknown field", child))
+			}
+		}
+	}
+}
+
+func schemaTypes(value interface{}) []string {
+	switch typed := value.(type) {
+	case string:
+		return []string{typed}
+	case []interface{}:
+		types := make([]string, 0, len(typed))
+		for _, item := range typed {
+			if name, ok := item.(string); ok {
+				types = append(types, name)
+			}
+		}
+		return types
+	default:
+		return nil
+	}
+}
+
+func matchesAnyType(value interface{}, types []string) bool {
+	for _, name := range types {
+		if matchesType(value, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchesType(value interface{}, name string) bool {
+	switch name {
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		number, ok := value.(float64)
+		return ok && number == math.Trunc(number)
+	case "null":
+		return value == nil
+	default:
+		// Unknown type names are not ours to reject.
+		return true
+	}
+}
+
+func jsonTypeName(value interface{}) string {
+	switch typed := value.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		if typed == math.Trunc(typed) {
+			return "integer"
+		}
+		return "number"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func containsJSONValue(values []interface{}, value interface{}) bool {
+	encoded := encodeJSON(value)
+
+	for _, candidate := range values {
+		if encodeJSON(candidate) == encoded {
+			return true
+		}
+	}
+
+	return false
+}
+
+func encodeJSON(value interface{}) string {
+	encoded, err := json.Marshal(value)
+
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+
+	return string(encoded)
+}
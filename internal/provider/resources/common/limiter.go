@@ -0,0 +1,81 @@
+package common
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// limitTransport is a token bucket shared by every client built on top of it,
+// so parallel Terraform operations together never exceed the configured rate.
+type limitTransport struct {
+	base     http.RoundTripper
+	interval time.Duration
+	burst    float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewLimitTransport wraps base so that at most requestsPerSecond requests are
+// sent on average, with bursts of up to burst requests. A non-positive rate
+// disables limiting.
+func NewLimitTransport(base http.RoundTripper, requestsPerSecond float64, burst int) http.RoundTripper {
+	if requestsPerSecond <= 0 {
+		return base
+	}
+
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &limitTransport{
+		base:     base,
+		interval: time.Duration(float64(time.Second) / requestsPerSecond),
+		burst:    float64(burst),
+		tokens:   float64(burst),
+		last:     time.Now(),
+	}
+}
+
+func (t *limitTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	wait := t.reserve()
+
+	if wait > 0 {
+		timer := time.NewTimer(wait)
+
+		select {
+		case <-request.Context().Done():
+			timer.Stop()
+			return nil, request.Context().Err()
+		case <-timer.C:
+		}
+	}
+
+	return t.base.RoundTrip(request)
+}
+
+// reserve takes a token and returns how long the caller has to wait before the
+// token becomes available. Tokens may go negative, which queues callers in order.
+func (t *limitTransport) reserve() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+
+	t.tokens += float64(now.Sub(t.last)) / float64(t.interval)
+	t.last = now
+
+	if t.tokens > t.burst {
+		t.tokens = t.burst
+	}
+
+	t.tokens--
+
+	if t.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-t.tokens * float64(t.interval))
+}
@@ -0,0 +1,116 @@
+package common
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// PreserveUnmanagedMetadataAttribute is the preserve_unmanaged_metadata
+// attribute shared by every resource with metadata.
+func PreserveUnmanagedMetadataAttribute() schema.BoolAttribute {
+	return schema.BoolAttribute{
+		Optional:    true,
+		Computed:    true,
+		Default:     booldefault.StaticBool(false),
+		Description: "When true, Terraform only manages the metadata keys listed in metadata and keeps keys written by other tools.",
+	}
+}
+
+// MetadataElements returns the elements of a metadata attribute. ok is false
+// when the value is null or unknown, i.e. Terraform has no opinion about it.
+func MetadataElements(ctx context.Context, value types.Map) (map[string]string, bool, diag.Diagnostics) {
+	if value.IsNull() || value.IsUnknown() {
+		return nil, false, nil
+	}
+
+	elements := map[string]string{}
+	diags := value.ElementsAs(ctx, &elements, false)
+
+	return elements, true, diags
+}
+
+// MetadataToSend works out the metadata to write. Unless preserveUnmanaged is
+// set, planned replaces whatever is stored in flespi. Otherwise only the keys
+// Terraform manages are touched: keys dropped from the configuration since
+// prior are removed, planned keys are set, and every other key in remote,
+// written by other tools, is kept as is.
+func MetadataToSend(planned, prior, remote map[string]string, preserveUnmanaged bool) map[string]string {
+	if !preserveUnmanaged {
+		return planned
+	}
+
+	merged := make(map[string]string, len(remote)+len(planned))
+
+	for key, value := range remote {
+		merged[key] = value
+	}
+
+	for key := range prior {
+		if _, ok := planned[key]; !ok {
+			delete(merged, key)
+		}
+	}
+
+	for key, value := range planned {
+		merged[key] = value
+	}
+
+	return merged
+}
+
+// ManagedMetadata returns the part of remote that belongs in state: all of it,
+// or with preserveUnmanaged only the keys listed in managed, so keys written by
+// other tools never show up as drift. The result is never nil, so an object
+// without metadata matches an empty map in configuration.
+func ManagedMetadata(remote, managed map[string]string, preserveUnmanaged bool) map[string]string {
+	if !preserveUnmanaged {
+		if remote == nil {
+			return map[string]string{}
+		}
+
+		return remote
+	}
+
+	result := make(map[string]string, len(managed))
+
+	for key := range managed {
+		if value, ok := remote[key]; ok {
+			result[key] = value
+		}
+	}
+
+	return result
+}
+
+// MetadataValue converts remote metadata into the state value. managed is the
+// metadata attribute Terraform owns, taken from the plan after a write or from
+// the prior state on refresh. An unset attribute stays null while flespi holds
+// no metadata for it.
+func MetadataValue(ctx context.Context, remote map[string]string, managed types.Map, preserveUnmanaged bool) (types.Map, diag.Diagnostics) {
+	owned, _, diags := MetadataElements(ctx, managed)
+
+	result := ManagedMetadata(remote, owned, preserveUnmanaged)
+
+	if len(result) == 0 && managed.IsNull() {
+		return types.MapNull(types.StringType), diags
+	}
+
+	value, valueDiags := types.MapValueFrom(ctx, types.StringType, result)
+	diags.Append(valueDiags...)
+
+	return value, diags
+}
+
+// OwnedMetadata picks the metadata value Terraform owns after an update: the
+// planned one, or the prior state when the plan leaves it to the provider.
+func OwnedMetadata(planned, prior types.Map) types.Map {
+	if planned.IsUnknown() {
+		return prior
+	}
+
+	return planned
+}
@@ -0,0 +1,170 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const retryInitialWait = 1 * time.Second
+
+// retryTransport retries flespi API calls that failed because the account hit
+// its API limits (429) or because of a transient server error. It replays the
+// buffered request body, so it is safe for POST and PUT payloads.
+type retryTransport struct {
+	ctx        context.Context
+	base       http.RoundTripper
+	maxRetries int
+	maxWait    time.Duration
+}
+
+// NewRetryTransport wraps base with retries using exponential backoff. A
+// Retry-After header, which flespi sends while an account is blocked for the
+// limit's blocking_duration, takes precedence over the computed backoff. No
+// single wait is longer than maxWait. Retries are logged through ctx: the
+// client library sends its requests with context.Background(), so their own
+// context carries no Terraform logger.
+func NewRetryTransport(ctx context.Context, base http.RoundTripper, maxRetries int, maxWait time.Duration) http.RoundTripper {
+	if maxRetries <= 0 {
+		return base
+	}
+
+	return &retryTransport{
+		ctx:        ctx,
+		base:       base,
+		maxRetries: maxRetries,
+		maxWait:    maxWait,
+	}
+}
+
+func (t *retryTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	var body []byte
+
+	if request.Body != nil && request.Body != http.NoBody {
+		var err error
+
+		body, err = io.ReadAll(request.Body)
+		request.Body.Close()
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		attemptRequest := request.Clone(request.Context())
+
+		if body != nil {
+			attemptRequest.Body = io.NopCloser(bytes.NewReader(body))
+			attemptRequest.GetBody = func() (io.ReadCloser, error) {
+				return io.NopCloser(bytes.NewReader(body)), nil
+			}
+		}
+
+		response, err := t.base.RoundTrip(attemptRequest)
+
+		if attempt >= t.maxRetries || !t.shouldRetry(request.Method, response, err) {
+			return response, err
+		}
+
+		wait := t.backoff(attempt, response)
+
+		if response != nil {
+			// Drain the body so the connection can be reused.
+			_, _ = io.Copy(io.Discard, response.Body)
+			response.Body.Close()
+		}
+
+		tflog.Warn(t.ctx, "Flespi API request failed, retrying", map[string]interface{}{
+			"method":      request.Method,
+			"path":        request.URL.Path,
+			"failure":     describeFailure(response, err),
+			"wait":        wait.String(),
+			"attempt":     attempt + 1,
+			"max_retries": t.maxRetries,
+		})
+
+		timer := time.NewTimer(wait)
+
+		select {
+		case <-request.Context().Done():
+			timer.Stop()
+			return nil, request.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// shouldRetry retries 429 for every method. Server errors and network failures
+// are only retried for idempotent methods, as a failed POST may still have
+// created the object.
+func (t *retryTransport) shouldRetry(method string, response *http.Response, err error) bool {
+	idempotent := method != http.MethodPost
+
+	if err != nil {
+		return idempotent
+	}
+
+	switch response.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return idempotent
+	default:
+		return false
+	}
+}
+
+func (t *retryTransport) backoff(attempt int, response *http.Response) time.Duration {
+	wait := time.Duration(float64(retryInitialWait) * math.Pow(2, float64(attempt)))
+
+	if response != nil {
+		if retryAfter, ok := parseRetryAfter(response.Header.Get("Retry-After")); ok {
+			wait = retryAfter
+		}
+	}
+
+	if t.maxWait > 0 && wait > t.maxWait {
+		wait = t.maxWait
+	}
+
+	return wait
+}
+
+// parseRetryAfter understands both the delay-seconds and the HTTP-date forms.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		wait := time.Until(date)
+
+		if wait < 0 {
+			wait = 0
+		}
+
+		return wait, true
+	}
+
+	return 0, false
+}
+
+func describeFailure(response *http.Response, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+
+	return fmt.Sprintf("status %d", response.StatusCode)
+}
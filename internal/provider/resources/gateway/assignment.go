@@ -0,0 +1,405 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"terraform-provider-flespi/internal/provider/resources/common"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	flespi "github.com/mixser/flespi-client"
+)
+
+var (
+	_ resource.Resource                = &gwAssignmentResource{}
+	_ resource.ResourceWithConfigure   = &gwAssignmentResource{}
+	_ resource.ResourceWithImportState = &gwAssignmentResource{}
+)
+
+// assignmentKind describes a link between two flespi objects managed through
+// the gw/<parent>/<parent-id>/<child>/<child-id> endpoints, e.g. a calculator
+// assigned to a device.
+type assignmentKind struct {
+	typeName    string
+	description string
+
+	parentEndpoint  string
+	parentAttribute string
+	parentTitle     string
+
+	childEndpoint  string
+	childAttribute string
+	childTitle     string
+}
+
+// gwAssignmentResource manages a single assignment. The parent and child
+// attributes are named after the kind, so the model is read attribute by
+// attribute rather than through a struct.
+type gwAssignmentResource struct {
+	kind     assignmentKind
+	provider *flespi.Client
+}
+
+type assignmentsResponse struct {
+	Result []json.RawMessage `json:"result"`
+}
+
+// assignmentEndpoint addresses the children of parentId matched by the flespi
+// selector children, e.g. "42", "1,2,3" or "all".
+func assignmentEndpoint(kind assignmentKind, parentId int64, children string) string {
+	return fmt.Sprintf("gw/%s/%d/%s/%s", kind.parentEndpoint, parentId, kind.childEndpoint, children)
+}
+
+func createAssignment(client *flespi.Client, kind assignmentKind, parentId, childId int64) error {
+	return client.RequestAPI("POST", assignmentEndpoint(kind, parentId, idSelector([]int64{childId})), nil, nil)
+}
+
+// assignmentExists reports whether childId is still assigned to parentId. A
+// missing parent or child counts as a missing assignment.
+func assignmentExists(client *flespi.Client, kind assignmentKind, parentId, childId int64) (bool, error) {
+	response := assignmentsResponse{}
+
+	err := client.RequestAPI("GET", assignmentEndpoint(kind, parentId, idSelector([]int64{childId})), nil, &response)
+
+	if common.IsNotFound(err) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return len(response.Result) > 0, nil
+}
+
+type assignmentParentsResponse struct {
+	Parents []struct {
+		AccountId int64 `json:"cid"`
+	} `json:"result"`
+}
+
+// parentAccount returns the account that owns parentId, and so both ends of
+// its assignments, as flespi reports it rather than as configured.
+func parentAccount(client *flespi.Client, kind assignmentKind, parentId int64) (int64, error) {
+	response := assignmentParentsResponse{}
+
+	endpoint := fmt.Sprintf("gw/%s/%d?fields=cid", kind.parentEndpoint, parentId)
+
+	if err := client.RequestAPI("GET", endpoint, nil, &response); err != nil {
+		return 0, err
+	}
+
+	if len(response.Parents) == 0 {
+		return 0, &flespi.APIError{StatusCode: 404, Method: "GET", Endpoint: endpoint, Message: kind.parentTitle + " not found"}
+	}
+
+	return response.Parents[0].AccountId, nil
+}
+
+func deleteAssignment(client *flespi.Client, kind assignmentKind, parentId, childId int64) error {
+	return client.RequestAPI("DELETE", assignmentEndpoint(kind, parentId, idSelector([]int64{childId})), nil, nil)
+}
+
+// idSelector renders ids as a flespi selector addressing all of them at once.
+func idSelector(ids []int64) string {
+	parts := make([]string, 0, len(ids))
+
+	for _, id := range ids {
+		parts = append(parts, strconv.FormatInt(id, 10))
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// diffIds returns the ids of want missing from have, and those of have
+// missing from want.
+func diffIds(have, want []int64) ([]int64, []int64) {
+	haveSet := make(map[int64]bool, len(have))
+	wantSet := make(map[int64]bool, len(want))
+
+	for _, id := range have {
+		haveSet[id] = true
+	}
+
+	for _, id := range want {
+		wantSet[id] = true
+	}
+
+	var added, removed []int64
+
+	for _, id := range want {
+		if !haveSet[id] {
+			added = append(added, id)
+		}
+	}
+
+	for _, id := range have {
+		if !wantSet[id] {
+			removed = append(removed, id)
+		}
+	}
+
+	return added, removed
+}
+
+func (g *gwAssignmentResource) Metadata(ctx context.Context, request resource.MetadataRequest, response *resource.MetadataResponse) {
+	response.TypeName = request.ProviderTypeName + "_" + g.kind.typeName
+}
+
+func (g *gwAssignmentResource) Configure(ctx context.Context, request resource.ConfigureRequest, response *resource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	client, ok := request.ProviderData.(*flespi.Client)
+
+	if !ok {
+		response.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *flespi.Client, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+		)
+
+		return
+	}
+
+	g.provider = client
+}
+
+func (g *gwAssignmentResource) Schema(ctx context.Context, request resource.SchemaRequest, response *resource.SchemaResponse) {
+	response.Schema = schema.Schema{
+		Description: g.kind.description,
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: fmt.Sprintf("Assignment ID in the form %s/%s.", g.kind.parentAttribute, g.kind.childAttribute),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			g.kind.parentAttribute: schema.Int64Attribute{
+				Required:    true,
+				Description: fmt.Sprintf("ID of the %s.", g.kind.parentTitle),
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			g.kind.childAttribute: schema.Int64Attribute{
+				Required:    true,
+				Description: fmt.Sprintf("ID of the %s to assign the %s to.", g.kind.childTitle, g.kind.parentTitle),
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"account_id": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Subaccount owning both objects.",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+		},
+	}
+}
+
+// assignmentState is the state of an assignment, read attribute by attribute
+// as the parent and child attribute names depend on the kind.
+type assignmentState struct {
+	parentId  types.Int64
+	childId   types.Int64
+	accountId types.Int64
+}
+
+type attributeGetter interface {
+	GetAttribute(ctx context.Context, path path.Path, target interface{}) diag.Diagnostics
+}
+
+type attributeSetter interface {
+	SetAttribute(ctx context.Context, path path.Path, value interface{}) diag.Diagnostics
+}
+
+func (g *gwAssignmentResource) read(ctx context.Context, source attributeGetter) (assignmentState, diag.Diagnostics) {
+	var data assignmentState
+	var diags diag.Diagnostics
+
+	diags.Append(source.GetAttribute(ctx, path.Root(g.kind.parentAttribute), &data.parentId)...)
+	diags.Append(source.GetAttribute(ctx, path.Root(g.kind.childAttribute), &data.childId)...)
+	diags.Append(source.GetAttribute(ctx, path.Root("account_id"), &data.accountId)...)
+
+	return data, diags
+}
+
+func (g *gwAssignmentResource) write(ctx context.Context, target attributeSetter, data assignmentState) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	diags.Append(target.SetAttribute(ctx, path.Root("id"), fmt.Sprintf("%d/%d", data.parentId.ValueInt64(), data.childId.ValueInt64()))...)
+	diags.Append(target.SetAttribute(ctx, path.Root(g.kind.parentAttribute), data.parentId)...)
+	diags.Append(target.SetAttribute(ctx, path.Root(g.kind.childAttribute), data.childId)...)
+	diags.Append(target.SetAttribute(ctx, path.Root("account_id"), data.accountId)...)
+
+	return diags
+}
+
+func (g *gwAssignmentResource) Create(ctx context.Context, request resource.CreateRequest, response *resource.CreateResponse) {
+	data, diags := g.read(ctx, request.Plan)
+
+	response.Diagnostics.Append(diags...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	client := common.ForAccount(g.provider, data.accountId.ValueInt64())
+
+	accountId, err := parentAccount(client, g.kind, data.parentId.ValueInt64())
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Failed to create assignment",
+			fmt.Sprintf("Error reading %s %d: %s", g.kind.parentTitle, data.parentId.ValueInt64(), err),
+		)
+		return
+	}
+
+	data.accountId = types.Int64Value(accountId)
+
+	if err := createAssignment(client, g.kind, data.parentId.ValueInt64(), data.childId.ValueInt64()); err != nil {
+		response.Diagnostics.AddError(
+			"Failed to create assignment",
+			fmt.Sprintf("Error assigning %s %d to %s %d: %s", g.kind.parentTitle, data.parentId.ValueInt64(), g.kind.childTitle, data.childId.ValueInt64(), err),
+		)
+		return
+	}
+
+	response.Diagnostics.Append(g.write(ctx, &response.State, data)...)
+}
+
+func (g *gwAssignmentResource) Read(ctx context.Context, request resource.ReadRequest, response *resource.ReadResponse) {
+	data, diags := g.read(ctx, request.State)
+
+	response.Diagnostics.Append(diags...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	client := common.ForAccount(g.provider, data.accountId.ValueInt64())
+
+	exists, err := assignmentExists(client, g.kind, data.parentId.ValueInt64(), data.childId.ValueInt64())
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error Reading Flespi Assignment",
+			fmt.Sprintf("Could not read assignment of %s %d to %s %d: %s", g.kind.parentTitle, data.parentId.ValueInt64(), g.kind.childTitle, data.childId.ValueInt64(), err),
+		)
+		return
+	}
+
+	if !exists {
+		tflog.Warn(ctx, "Flespi assignment not found, removing from state", map[string]interface{}{
+			g.kind.parentAttribute: data.parentId.ValueInt64(),
+			g.kind.childAttribute:  data.childId.ValueInt64(),
+		})
+		response.State.RemoveResource(ctx)
+		return
+	}
+
+	accountId, err := parentAccount(client, g.kind, data.parentId.ValueInt64())
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error Reading Flespi Assignment",
+			fmt.Sprintf("Could not read %s %d: %s", g.kind.parentTitle, data.parentId.ValueInt64(), err),
+		)
+		return
+	}
+
+	data.accountId = types.Int64Value(accountId)
+
+	response.Diagnostics.Append(g.write(ctx, &response.State, data)...)
+}
+
+// Update only runs when nothing but the computed attributes changed, since
+// every configurable attribute forces a replacement.
+func (g *gwAssignmentResource) Update(ctx context.Context, request resource.UpdateRequest, response *resource.UpdateResponse) {
+	data, diags := g.read(ctx, request.Plan)
+
+	response.Diagnostics.Append(diags...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	response.Diagnostics.Append(g.write(ctx, &response.State, data)...)
+}
+
+func (g *gwAssignmentResource) Delete(ctx context.Context, request resource.DeleteRequest, response *resource.DeleteResponse) {
+	data, diags := g.read(ctx, request.State)
+
+	response.Diagnostics.Append(diags...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	client := common.ForAccount(g.provider, data.accountId.ValueInt64())
+
+	err := deleteAssignment(client, g.kind, data.parentId.ValueInt64(), data.childId.ValueInt64())
+
+	if err != nil && !common.IsNotFound(err) {
+		response.Diagnostics.AddError(
+			"Error Deleting Flespi Assignment",
+			fmt.Sprintf("Could not unassign %s %d from %s %d: %s", g.kind.parentTitle, data.parentId.ValueInt64(), g.kind.childTitle, data.childId.ValueInt64(), err),
+		)
+		return
+	}
+}
+
+// ImportState accepts "parent_id/child_id" or, for objects living in a
+// subaccount, "account_id/parent_id/child_id".
+func (g *gwAssignmentResource) ImportState(ctx context.Context, request resource.ImportStateRequest, response *resource.ImportStateResponse) {
+	parts := strings.Split(strings.TrimSpace(request.ID), "/")
+	ids := make([]int64, 0, len(parts))
+
+	for _, part := range parts {
+		id, err := strconv.ParseInt(part, 10, 64)
+
+		if err != nil {
+			ids = nil
+			break
+		}
+
+		ids = append(ids, id)
+	}
+
+	if len(ids) != 2 && len(ids) != 3 {
+		response.Diagnostics.AddError(
+			"Invalid Import ID",
+			fmt.Sprintf("Expected \"%[1]s/%[2]s\" or \"account_id/%[1]s/%[2]s\", got: %[3]q", g.kind.parentAttribute, g.kind.childAttribute, request.ID),
+		)
+		return
+	}
+
+	data := assignmentState{accountId: types.Int64Value(0)}
+
+	if len(ids) == 3 {
+		data.accountId = types.Int64Value(ids[0])
+		ids = ids[1:]
+	}
+
+	data.parentId = types.Int64Value(ids[0])
+	data.childId = types.Int64Value(ids[1])
+
+	response.Diagnostics.Append(g.write(ctx, &response.State, data)...)
+}
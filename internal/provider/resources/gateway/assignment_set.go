@@ -0,0 +1,351 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"terraform-provider-flespi/internal/provider/resources/common"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	flespi "github.com/mixser/flespi-client"
+)
+
+var (
+	_ resource.Resource                = &gwAssignmentSetResource{}
+	_ resource.ResourceWithConfigure   = &gwAssignmentSetResource{}
+	_ resource.ResourceWithImportState = &gwAssignmentSetResource{}
+)
+
+// gwAssignmentSetResource manages the assignment of a parent to a set of
+// children in one resource. Only the listed children are managed: others
+// assigned outside of Terraform, e.g. by a gwAssignmentResource, are left
+// alone and don't show up as drift.
+type gwAssignmentSetResource struct {
+	kind              assignmentKind
+	childrenAttribute string
+	provider          *flespi.Client
+}
+
+// assignmentSetState is the state of an assignment set, read attribute by
+// attribute as the parent and children attribute names depend on the kind.
+type assignmentSetState struct {
+	parentId  types.Int64
+	childIds  types.Set
+	accountId types.Int64
+}
+
+type assignedItemsResponse struct {
+	Items []struct {
+		Id int64 `json:"id"`
+	} `json:"result"`
+}
+
+// listAssignments returns the IDs of all children assigned to parentId.
+func listAssignments(client *flespi.Client, kind assignmentKind, parentId int64) ([]int64, error) {
+	response := assignedItemsResponse{}
+
+	if err := client.RequestAPI("GET", assignmentEndpoint(kind, parentId, "all")+"?fields=id", nil, &response); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, 0, len(response.Items))
+
+	for _, item := range response.Items {
+		ids = append(ids, item.Id)
+	}
+
+	return ids, nil
+}
+
+// intersectIds returns the ids of want that are also in have.
+func intersectIds(want, have []int64) []int64 {
+	haveSet := make(map[int64]bool, len(have))
+
+	for _, id := range have {
+		haveSet[id] = true
+	}
+
+	result := make([]int64, 0, len(want))
+
+	for _, id := range want {
+		if haveSet[id] {
+			result = append(result, id)
+		}
+	}
+
+	return result
+}
+
+func createAssignments(client *flespi.Client, kind assignmentKind, parentId int64, childIds []int64) error {
+	if len(childIds) == 0 {
+		return nil
+	}
+
+	return client.RequestAPI("POST", assignmentEndpoint(kind, parentId, idSelector(childIds)), nil, nil)
+}
+
+func deleteAssignments(client *flespi.Client, kind assignmentKind, parentId int64, childIds []int64) error {
+	if len(childIds) == 0 {
+		return nil
+	}
+
+	return client.RequestAPI("DELETE", assignmentEndpoint(kind, parentId, idSelector(childIds)), nil, nil)
+}
+
+func (g *gwAssignmentSetResource) Metadata(ctx context.Context, request resource.MetadataRequest, response *resource.MetadataResponse) {
+	response.TypeName = request.ProviderTypeName + "_" + g.kind.typeName
+}
+
+func (g *gwAssignmentSetResource) Configure(ctx context.Context, request resource.ConfigureRequest, response *resource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	client, ok := request.ProviderData.(*flespi.Client)
+
+	if !ok {
+		response.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *flespi.Client, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+		)
+
+		return
+	}
+
+	g.provider = client
+}
+
+func (g *gwAssignmentSetResource) Schema(ctx context.Context, request resource.SchemaRequest, response *resource.SchemaResponse) {
+	response.Schema = schema.Schema{
+		Description: g.kind.description,
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: fmt.Sprintf("Same as %s.", g.kind.parentAttribute),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			g.kind.parentAttribute: schema.Int64Attribute{
+				Required:    true,
+				Description: fmt.Sprintf("ID of the %s.", g.kind.parentTitle),
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			g.childrenAttribute: schema.SetAttribute{
+				Required:    true,
+				ElementType: types.Int64Type,
+				Description: fmt.Sprintf("IDs of the %ss to assign the %s to. Other %ss assigned outside of Terraform are left alone.", g.kind.childTitle, g.kind.parentTitle, g.kind.childTitle),
+			},
+			"account_id": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Subaccount owning the objects.",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+		},
+	}
+}
+
+func (g *gwAssignmentSetResource) read(ctx context.Context, source attributeGetter) (assignmentSetState, []int64, diag.Diagnostics) {
+	var data assignmentSetState
+	var diags diag.Diagnostics
+
+	diags.Append(source.GetAttribute(ctx, path.Root(g.kind.parentAttribute), &data.parentId)...)
+	diags.Append(source.GetAttribute(ctx, path.Root(g.childrenAttribute), &data.childIds)...)
+	diags.Append(source.GetAttribute(ctx, path.Root("account_id"), &data.accountId)...)
+
+	var childIds []int64
+
+	if !data.childIds.IsNull() && !data.childIds.IsUnknown() {
+		diags.Append(data.childIds.ElementsAs(ctx, &childIds, false)...)
+	}
+
+	return data, childIds, diags
+}
+
+func (g *gwAssignmentSetResource) write(ctx context.Context, target attributeSetter, data assignmentSetState, childIds []int64) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	children, valueDiags := types.SetValueFrom(ctx, types.Int64Type, childIds)
+	diags.Append(valueDiags...)
+
+	diags.Append(target.SetAttribute(ctx, path.Root("id"), strconv.FormatInt(data.parentId.ValueInt64(), 10))...)
+	diags.Append(target.SetAttribute(ctx, path.Root(g.kind.parentAttribute), data.parentId)...)
+	diags.Append(target.SetAttribute(ctx, path.Root(g.childrenAttribute), children)...)
+	diags.Append(target.SetAttribute(ctx, path.Root("account_id"), data.accountId)...)
+
+	return diags
+}
+
+func (g *gwAssignmentSetResource) Create(ctx context.Context, request resource.CreateRequest, response *resource.CreateResponse) {
+	data, childIds, diags := g.read(ctx, request.Plan)
+
+	response.Diagnostics.Append(diags...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	client := common.ForAccount(g.provider, data.accountId.ValueInt64())
+
+	accountId, err := parentAccount(client, g.kind, data.parentId.ValueInt64())
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Failed to create assignments",
+			fmt.Sprintf("Error reading %s %d: %s", g.kind.parentTitle, data.parentId.ValueInt64(), err),
+		)
+		return
+	}
+
+	data.accountId = types.Int64Value(accountId)
+
+	if err := createAssignments(client, g.kind, data.parentId.ValueInt64(), childIds); err != nil {
+		response.Diagnostics.AddError(
+			"Failed to create assignments",
+			fmt.Sprintf("Error assigning %s %d to %ss %s: %s", g.kind.parentTitle, data.parentId.ValueInt64(), g.kind.childTitle, idSelector(childIds), err),
+		)
+		return
+	}
+
+	response.Diagnostics.Append(g.write(ctx, &response.State, data, childIds)...)
+}
+
+func (g *gwAssignmentSetResource) Read(ctx context.Context, request resource.ReadRequest, response *resource.ReadResponse) {
+	data, childIds, diags := g.read(ctx, request.State)
+
+	response.Diagnostics.Append(diags...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	client := common.ForAccount(g.provider, data.accountId.ValueInt64())
+
+	assigned, err := listAssignments(client, g.kind, data.parentId.ValueInt64())
+
+	if common.IsNotFound(err) {
+		tflog.Warn(ctx, "Flespi "+g.kind.parentTitle+" not found, removing assignments from state", map[string]interface{}{
+			g.kind.parentAttribute: data.parentId.ValueInt64(),
+		})
+		response.State.RemoveResource(ctx)
+		return
+	}
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error Reading Flespi Assignments",
+			fmt.Sprintf("Could not list %ss of %s %d: %s", g.kind.childTitle, g.kind.parentTitle, data.parentId.ValueInt64(), err),
+		)
+		return
+	}
+
+	accountId, err := parentAccount(client, g.kind, data.parentId.ValueInt64())
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error Reading Flespi Assignments",
+			fmt.Sprintf("Could not read %s %d: %s", g.kind.parentTitle, data.parentId.ValueInt64(), err),
+		)
+		return
+	}
+
+	data.accountId = types.Int64Value(accountId)
+
+	// After an import no children are known yet, so all assigned ones are
+	// taken over; otherwise only the managed ones that are still assigned.
+	if !data.childIds.IsNull() {
+		assigned = intersectIds(childIds, assigned)
+	}
+
+	response.Diagnostics.Append(g.write(ctx, &response.State, data, assigned)...)
+}
+
+func (g *gwAssignmentSetResource) Update(ctx context.Context, request resource.UpdateRequest, response *resource.UpdateResponse) {
+	data, childIds, diags := g.read(ctx, request.Plan)
+
+	response.Diagnostics.Append(diags...)
+
+	_, priorIds, diags := g.read(ctx, request.State)
+
+	response.Diagnostics.Append(diags...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	client := common.ForAccount(g.provider, data.accountId.ValueInt64())
+
+	added, removed := diffIds(priorIds, childIds)
+
+	if err := createAssignments(client, g.kind, data.parentId.ValueInt64(), added); err != nil {
+		response.Diagnostics.AddError(
+			"Error Updating Flespi Assignments",
+			fmt.Sprintf("Could not assign %s %d to %ss %s: %s", g.kind.parentTitle, data.parentId.ValueInt64(), g.kind.childTitle, idSelector(added), err),
+		)
+		return
+	}
+
+	if err := deleteAssignments(client, g.kind, data.parentId.ValueInt64(), removed); err != nil && !common.IsNotFound(err) {
+		response.Diagnostics.AddError(
+			"Error Updating Flespi Assignments",
+			fmt.Sprintf("Could not unassign %s %d from %ss %s: %s", g.kind.parentTitle, data.parentId.ValueInt64(), g.kind.childTitle, idSelector(removed), err),
+		)
+		return
+	}
+
+	response.Diagnostics.Append(g.write(ctx, &response.State, data, childIds)...)
+}
+
+func (g *gwAssignmentSetResource) Delete(ctx context.Context, request resource.DeleteRequest, response *resource.DeleteResponse) {
+	data, childIds, diags := g.read(ctx, request.State)
+
+	response.Diagnostics.Append(diags...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	client := common.ForAccount(g.provider, data.accountId.ValueInt64())
+
+	err := deleteAssignments(client, g.kind, data.parentId.ValueInt64(), childIds)
+
+	if err != nil && !common.IsNotFound(err) {
+		response.Diagnostics.AddError(
+			"Error Deleting Flespi Assignments",
+			fmt.Sprintf("Could not unassign %s %d from %ss %s: %s", g.kind.parentTitle, data.parentId.ValueInt64(), g.kind.childTitle, idSelector(childIds), err),
+		)
+		return
+	}
+}
+
+// ImportState takes over every child currently assigned to the parent. It
+// accepts "parent_id" or "account_id/parent_id".
+func (g *gwAssignmentSetResource) ImportState(ctx context.Context, request resource.ImportStateRequest, response *resource.ImportStateResponse) {
+	accountId, parentId, err := common.ParseAccountScopedId(request.ID)
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Invalid Import ID",
+			fmt.Sprintf("Expected \"%[1]s\" or \"account_id/%[1]s\", got: %[2]q (%[3]s)", g.kind.parentAttribute, request.ID, err),
+		)
+		return
+	}
+
+	response.Diagnostics.Append(response.State.SetAttribute(ctx, path.Root("id"), strconv.FormatInt(parentId, 10))...)
+	response.Diagnostics.Append(response.State.SetAttribute(ctx, path.Root(g.kind.parentAttribute), parentId)...)
+	response.Diagnostics.Append(response.State.SetAttribute(ctx, path.Root("account_id"), accountId)...)
+}
@@ -0,0 +1,365 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"terraform-provider-flespi/internal/provider/resources/common"
+
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	flespi "github.com/mixser/flespi-client"
+	flespi_calculator "github.com/mixser/flespi-client/resources/gateway/calculator"
+)
+
+var (
+	_ resource.Resource                = &gwCalculatorResource{}
+	_ resource.ResourceWithConfigure   = &gwCalculatorResource{}
+	_ resource.ResourceWithImportState = &gwCalculatorResource{}
+)
+
+type gwCalculatorResource struct {
+	client   *flespi_calculator.CalculatorClient
+	provider *flespi.Client
+}
+
+type calculatorResourceModel struct {
+	Id   types.Int64  `tfsdk:"id"`
+	Name types.String `tfsdk:"name"`
+
+	Selectors jsontypes.Normalized `tfsdk:"selectors"`
+	Counters  jsontypes.Normalized `tfsdk:"counters"`
+
+	UpdateDelay     types.Int64 `tfsdk:"update_delay"`
+	IntervalsTTL    types.Int64 `tfsdk:"intervals_ttl"`
+	IntervalsRotate types.Int64 `tfsdk:"intervals_rotate"`
+
+	ValidateInterval types.String `tfsdk:"validate_interval"`
+	ValidateMessage  types.String `tfsdk:"validate_message"`
+
+	Metadata  types.Map   `tfsdk:"metadata"`
+	AccountId types.Int64 `tfsdk:"account_id"`
+
+	PreserveUnmanagedMetadata types.Bool `tfsdk:"preserve_unmanaged_metadata"`
+}
+
+func NewCalculatorResource() resource.Resource {
+	return &gwCalculatorResource{}
+}
+
+func (g *gwCalculatorResource) Metadata(ctx context.Context, request resource.MetadataRequest, response *resource.MetadataResponse) {
+	response.TypeName = request.ProviderTypeName + "_calculator"
+}
+
+func (g *gwCalculatorResource) Configure(ctx context.Context, request resource.ConfigureRequest, response *resource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	client, ok := request.ProviderData.(*flespi.Client)
+
+	if !ok {
+		response.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *flespi.Client, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+		)
+
+		return
+	}
+
+	g.client = client.Calculators
+	g.provider = client
+}
+
+// clientFor returns a calculator client acting as the (sub)account that owns the calculator.
+func (g *gwCalculatorResource) clientFor(accountId types.Int64) *flespi_calculator.CalculatorClient {
+	return common.ForAccount(g.provider, accountId.ValueInt64()).Calculators
+}
+
+func (g *gwCalculatorResource) Schema(ctx context.Context, request resource.SchemaRequest, response *resource.SchemaResponse) {
+	response.Schema = schema.Schema{
+		Description: "Analytics calculator splitting device messages into intervals and computing counters for them.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.Int64Attribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the calculator",
+			},
+			"selectors": schema.StringAttribute{
+				Required:    true,
+				CustomType:  jsontypes.NormalizedType{},
+				Description: "JSON array of selectors defining where intervals begin and end. Use jsonencode() in HCL.",
+			},
+			"counters": schema.StringAttribute{
+				Required:    true,
+				CustomType:  jsontypes.NormalizedType{},
+				Description: "JSON array of counters calculated for every interval. Use jsonencode() in HCL.",
+			},
+			"update_delay": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Delay in seconds before intervals are recalculated after new messages arrive",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"intervals_ttl": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "How long intervals are kept, in seconds",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"intervals_rotate": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Maximum age of intervals after which they are rotated, in seconds",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"validate_interval": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Expression an interval must match to be stored",
+			},
+			"validate_message": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Expression a message must match to be processed",
+			},
+			"metadata": schema.MapAttribute{
+				Optional:    true,
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Calculator metadata",
+			},
+			"account_id": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Subaccount ID to create the calculator under.",
+			},
+			"preserve_unmanaged_metadata": common.PreserveUnmanagedMetadataAttribute(),
+		},
+	}
+}
+
+func (g *gwCalculatorResource) Create(ctx context.Context, request resource.CreateRequest, response *resource.CreateResponse) {
+	var data *calculatorResourceModel
+
+	response.Diagnostics.Append(request.Plan.Get(ctx, &data)...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	instance := g.convertResourceModelToFlespiCalculator(ctx, *data)
+
+	calculator, err := createCalculator(g.provider, instance)
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Failed to create calculator",
+			fmt.Sprintf("Error creating calculator: %s", err),
+		)
+		return
+	}
+
+	calculator, err = getCalculator(common.ForAccount(g.provider, data.AccountId.ValueInt64()), calculator.Id)
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Failed to get calculator after creation",
+			fmt.Sprintf("Error reading calculator: %s", err),
+		)
+		return
+	}
+
+	result, diags := g.convertFlespiCalculatorToResourceModel(ctx, calculator, *data, data.Metadata)
+
+	response.Diagnostics.Append(diags...)
+	response.Diagnostics.Append(response.State.Set(ctx, &result)...)
+}
+
+func (g *gwCalculatorResource) Read(ctx context.Context, request resource.ReadRequest, response *resource.ReadResponse) {
+	var state calculatorResourceModel
+
+	diags := request.State.Get(ctx, &state)
+	response.Diagnostics.Append(diags...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	calculator, err := getCalculator(common.ForAccount(g.provider, state.AccountId.ValueInt64()), state.Id.ValueInt64())
+
+	if common.IsNotFound(err) {
+		tflog.Warn(ctx, "Flespi calculator not found, removing from state", map[string]interface{}{"id": state.Id.ValueInt64()})
+		response.State.RemoveResource(ctx)
+		return
+	}
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error Reading Flespi Calculator",
+			"Could not read Flespi calculator ID "+state.Id.String()+": "+err.Error(),
+		)
+
+		return
+	}
+
+	model, diags := g.convertFlespiCalculatorToResourceModel(ctx, calculator, state, state.Metadata)
+
+	response.Diagnostics.Append(diags...)
+	response.Diagnostics.Append(response.State.Set(ctx, model)...)
+}
+
+func (g *gwCalculatorResource) Update(ctx context.Context, request resource.UpdateRequest, response *resource.UpdateResponse) {
+	var plan calculatorResourceModel
+	var state calculatorResourceModel
+
+	response.Diagnostics.Append(request.Plan.Get(ctx, &plan)...)
+	response.Diagnostics.Append(request.State.Get(ctx, &state)...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Id = state.Id
+
+	var calculator = g.convertResourceModelToFlespiCalculator(ctx, plan)
+
+	calculator.AccountId = state.AccountId.ValueInt64()
+
+	if plan.PreserveUnmanagedMetadata.ValueBool() && calculator.Metadata != nil {
+		current, err := getCalculator(common.ForAccount(g.provider, state.AccountId.ValueInt64()), state.Id.ValueInt64())
+
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Error Reading Flespi Calculator",
+				"Could not read current calculator metadata: "+err.Error(),
+			)
+			return
+		}
+
+		prior, _, diags := common.MetadataElements(ctx, state.Metadata)
+		response.Diagnostics.Append(diags...)
+
+		metadata := common.MetadataToSend(*calculator.Metadata, prior, current.metadata(), true)
+		calculator.Metadata = &metadata
+	}
+
+	_, err := updateCalculator(g.provider, calculator)
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error Updating Flespi Calculator",
+			"Could not update calculator, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	updatedCalculator, err := getCalculator(common.ForAccount(g.provider, state.AccountId.ValueInt64()), state.Id.ValueInt64())
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error Reading Flespi Calculator",
+			"Could not read calculator Id: "+state.Id.String()+": "+err.Error(),
+		)
+		return
+	}
+
+	model, diags := g.convertFlespiCalculatorToResourceModel(ctx, updatedCalculator, plan, common.OwnedMetadata(plan.Metadata, state.Metadata))
+
+	response.Diagnostics.Append(diags...)
+	response.Diagnostics.Append(response.State.Set(ctx, model)...)
+}
+
+func (g *gwCalculatorResource) Delete(ctx context.Context, request resource.DeleteRequest, response *resource.DeleteResponse) {
+	var state calculatorResourceModel
+
+	diags := request.State.Get(ctx, &state)
+
+	response.Diagnostics.Append(diags...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	err := g.clientFor(state.AccountId).DeleteById(state.Id.ValueInt64())
+
+	if err != nil && !common.IsNotFound(err) {
+		response.Diagnostics.AddError(
+			"Error Deleting Flespi Calculator",
+			"Could not delete calculator, unexpected error: "+err.Error(),
+		)
+		return
+	}
+}
+
+func (g *gwCalculatorResource) ImportState(ctx context.Context, request resource.ImportStateRequest, response *resource.ImportStateResponse) {
+	common.ImportStateByAccountScopedId(ctx, request, response)
+}
+
+func (g *gwCalculatorResource) convertResourceModelToFlespiCalculator(ctx context.Context, data calculatorResourceModel) calculatorObject {
+	var metadata *map[string]string
+
+	if elements, ok, _ := common.MetadataElements(ctx, data.Metadata); ok {
+		metadata = &elements
+	}
+
+	return calculatorObject{
+		Id:               data.Id.ValueInt64(),
+		Name:             data.Name.ValueString(),
+		Selectors:        json.RawMessage(data.Selectors.ValueString()),
+		Counters:         json.RawMessage(data.Counters.ValueString()),
+		UpdateDelay:      int64Setting(data.UpdateDelay),
+		IntervalsTTL:     int64Setting(data.IntervalsTTL),
+		IntervalsRotate:  int64Setting(data.IntervalsRotate),
+		ValidateInterval: stringSetting(data.ValidateInterval),
+		ValidateMessage:  stringSetting(data.ValidateMessage),
+		Metadata:         metadata,
+		AccountId:        data.AccountId.ValueInt64(),
+	}
+}
+
+// convertFlespiCalculatorToResourceModel builds the state from calculator.
+// configured holds the selectors and counters Terraform last wrote; they are
+// kept as written when flespi only filled in defaults on its side.
+func (g *gwCalculatorResource) convertFlespiCalculatorToResourceModel(ctx context.Context, calculator *calculatorObject, configured calculatorResourceModel, managed types.Map) (*calculatorResourceModel, diag.Diagnostics) {
+	var state calculatorResourceModel
+	var diags diag.Diagnostics
+
+	state.Id = types.Int64Value(calculator.Id)
+	state.Name = types.StringValue(calculator.Name)
+	state.Selectors = common.PreferConfiguredJSON(configured.Selectors, rawJSONArrayString(calculator.Selectors))
+	state.Counters = common.PreferConfiguredJSON(configured.Counters, rawJSONArrayString(calculator.Counters))
+	state.UpdateDelay = types.Int64Value(settingValue(calculator.UpdateDelay))
+	state.IntervalsTTL = types.Int64Value(settingValue(calculator.IntervalsTTL))
+	state.IntervalsRotate = types.Int64Value(settingValue(calculator.IntervalsRotate))
+	state.ValidateInterval = types.StringValue(settingValue(calculator.ValidateInterval))
+	state.ValidateMessage = types.StringValue(settingValue(calculator.ValidateMessage))
+
+	meta, metaDiags := common.MetadataValue(ctx, calculator.metadata(), managed, configured.PreserveUnmanagedMetadata.ValueBool())
+	diags.Append(metaDiags...)
+	state.Metadata = meta
+	state.PreserveUnmanagedMetadata = types.BoolValue(configured.PreserveUnmanagedMetadata.ValueBool())
+
+	state.AccountId = types.Int64Value(calculator.AccountId)
+
+	return &state, diags
+}
@@ -0,0 +1,134 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"terraform-provider-flespi/internal/provider/resources/common"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	flespi "github.com/mixser/flespi-client"
+)
+
+// calculatorObject mirrors flespi_calculator.Calculator but keeps selectors and
+// counters as raw JSON. The client library decodes them into typed structs and
+// drops every field it doesn't know about, so they wouldn't round-trip.
+// Settings are pointers so that a configured zero or empty value is sent
+// while one Terraform does not know leaves flespi's value alone.
+// Metadata is a pointer so that a nil value leaves the stored metadata alone
+// while an empty map clears it.
+type calculatorObject struct {
+	Id               int64              `json:"id,omitempty"`
+	Name             string             `json:"name"`
+	Selectors        json.RawMessage    `json:"selectors"`
+	Counters         json.RawMessage    `json:"counters"`
+	UpdateDelay      *int64             `json:"update_delay,omitempty"`
+	IntervalsTTL     *int64             `json:"intervals_ttl,omitempty"`
+	IntervalsRotate  *int64             `json:"intervals_rotate,omitempty"`
+	ValidateInterval *string            `json:"validate_interval,omitempty"`
+	ValidateMessage  *string            `json:"validate_message,omitempty"`
+	Metadata         *map[string]string `json:"metadata,omitempty"`
+	AccountId        int64              `json:"cid,omitempty"`
+}
+
+// metadata returns the calculator metadata as read from the API.
+func (c calculatorObject) metadata() map[string]string {
+	if c.Metadata == nil {
+		return nil
+	}
+
+	return *c.Metadata
+}
+
+type calculatorsResponse struct {
+	Calculators []calculatorObject `json:"result"`
+}
+
+const calculatorFields = "id,name,selectors,counters,update_delay,intervals_ttl,intervals_rotate,validate_interval,validate_message,metadata,cid"
+
+func createCalculator(client *flespi.Client, calculator calculatorObject) (*calculatorObject, error) {
+	headers := common.AccountHeaders(calculator.AccountId)
+	calculator.AccountId = 0
+
+	response := calculatorsResponse{}
+
+	if err := client.RequestAPIWithHeaders("POST", "gw/calcs", headers, []calculatorObject{calculator}, &response); err != nil {
+		return nil, err
+	}
+
+	if len(response.Calculators) == 0 {
+		return nil, fmt.Errorf("flespi returned no calculator")
+	}
+
+	return &response.Calculators[0], nil
+}
+
+func getCalculator(client *flespi.Client, calculatorId int64) (*calculatorObject, error) {
+	response := calculatorsResponse{}
+
+	endpoint := fmt.Sprintf("gw/calcs/%d?fields=%s", calculatorId, calculatorFields)
+
+	if err := client.RequestAPI("GET", endpoint, nil, &response); err != nil {
+		return nil, err
+	}
+
+	if len(response.Calculators) == 0 {
+		return nil, &flespi.APIError{StatusCode: 404, Method: "GET", Endpoint: endpoint, Message: "calculator not found"}
+	}
+
+	return &response.Calculators[0], nil
+}
+
+func updateCalculator(client *flespi.Client, calculator calculatorObject) (*calculatorObject, error) {
+	calculatorId := calculator.Id
+	headers := common.AccountHeaders(calculator.AccountId)
+
+	calculator.Id = 0
+	calculator.AccountId = 0
+
+	response := calculatorsResponse{}
+
+	if err := client.RequestAPIWithHeaders("PUT", fmt.Sprintf("gw/calcs/%d", calculatorId), headers, calculator, &response); err != nil {
+		return nil, err
+	}
+
+	if len(response.Calculators) == 0 {
+		return nil, fmt.Errorf("flespi returned no calculator")
+	}
+
+	return &response.Calculators[0], nil
+}
+
+// int64Setting returns value for a request body, or nil when it is null or
+// not known yet so that the field is left out.
+func int64Setting(value types.Int64) *int64 {
+	if value.IsNull() || value.IsUnknown() {
+		return nil
+	}
+
+	setting := value.ValueInt64()
+
+	return &setting
+}
+
+// stringSetting is int64Setting for strings.
+func stringSetting(value types.String) *string {
+	if value.IsNull() || value.IsUnknown() {
+		return nil
+	}
+
+	setting := value.ValueString()
+
+	return &setting
+}
+
+// settingValue returns the setting read from the API, or its zero value when
+// flespi left it out.
+func settingValue[T any](setting *T) T {
+	var value T
+
+	if setting != nil {
+		value = *setting
+	}
+
+	return value
+}
@@ -0,0 +1,35 @@
+package gateway
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+)
+
+func NewCalculatorDeviceAssignmentResource() resource.Resource {
+	return &gwAssignmentResource{
+		kind: assignmentKind{
+			typeName:        "calculator_device_assignment",
+			description:     "Assigns a flespi calculator to a device, so intervals are calculated for the device messages.",
+			parentEndpoint:  "calcs",
+			parentAttribute: "calculator_id",
+			parentTitle:     "calculator",
+			childEndpoint:   "devices",
+			childAttribute:  "device_id",
+			childTitle:      "device",
+		},
+	}
+}
+
+func NewCalculatorGroupAssignmentResource() resource.Resource {
+	return &gwAssignmentResource{
+		kind: assignmentKind{
+			typeName:        "calculator_group_assignment",
+			description:     "Assigns a flespi calculator to a device group, so intervals are calculated for every device in the group.",
+			parentEndpoint:  "calcs",
+			parentAttribute: "calculator_id",
+			parentTitle:     "calculator",
+			childEndpoint:   "groups",
+			childAttribute:  "group_id",
+			childTitle:      "group",
+		},
+	}
+}
@@ -4,18 +4,29 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"terraform-provider-flespi/internal/provider/resources/common"
 
 	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	flespi "github.com/mixser/flespi-client"
 	flespi_channel "github.com/mixser/flespi-client/resources/gateway/channel"
 )
 
+var (
+	_ resource.Resource                = &gwChannelResource{}
+	_ resource.ResourceWithConfigure   = &gwChannelResource{}
+	_ resource.ResourceWithImportState = &gwChannelResource{}
+	_ resource.ResourceWithModifyPlan  = &gwChannelResource{}
+)
+
 type gwChannelResource struct {
-	client *flespi_channel.ChannelClient
+	client   *flespi_channel.ChannelClient
+	provider *flespi.Client
 }
 
 type channelResourceModel struct {
@@ -32,6 +43,8 @@ type channelResourceModel struct {
 	Configuration jsontypes.Normalized `tfsdk:"configuration"`
 	Metadata      types.Map            `tfsdk:"metadata"`
 	AccountId     types.Int64          `tfsdk:"account_id"`
+
+	PreserveUnmanagedMetadata types.Bool `tfsdk:"preserve_unmanaged_metadata"`
 }
 
 func NewChannelResource() resource.Resource {
@@ -70,7 +83,7 @@ func (g *gwChannelResource) Schema(ctx context.Context, request resource.SchemaR
 				Optional:    true,
 				Computed:    true,
 				CustomType:  jsontypes.NormalizedType{},
-				Description: "Protocol-specific configuration parameters as JSON. The available fields depend on the protocol; retrieve the schema with GET /gw/channel-protocols/{protocol_id}. Use jsonencode() in HCL.",
+				Description: "Protocol-specific configuration parameters as JSON. The available fields depend on the protocol; see the configuration_schema of the flespi_channel_protocol data source. Use jsonencode() in HCL.",
 			},
 			"metadata": schema.MapAttribute{
 				Optional:    true,
@@ -82,6 +95,7 @@ func (g *gwChannelResource) Schema(ctx context.Context, request resource.SchemaR
 				Computed:    true,
 				Description: "Subaccount ID to create the channel under.",
 			},
+			"preserve_unmanaged_metadata": common.PreserveUnmanagedMetadataAttribute(),
 		},
 	}
 }
@@ -103,6 +117,12 @@ func (g *gwChannelResource) Configure(ctx context.Context, request resource.Conf
 	}
 
 	g.client = client.Channels
+	g.provider = client
+}
+
+// clientFor returns a channel client acting as the (sub)account that owns the channel.
+func (g *gwChannelResource) clientFor(accountId types.Int64) *flespi_channel.ChannelClient {
+	return common.ForAccount(g.provider, accountId.ValueInt64()).Channels
 }
 
 func (g *gwChannelResource) Create(ctx context.Context, request resource.CreateRequest, response *resource.CreateResponse) {
@@ -154,7 +174,7 @@ func (g *gwChannelResource) Create(ctx context.Context, request resource.CreateR
 		return
 	}
 
-	channelInstance, err = g.client.Get(channelInstance.Id)
+	channelInstance, err = getChannel(common.ForAccount(g.provider, data.AccountId.ValueInt64()), channelInstance.Id)
 
 	if err != nil {
 		response.Diagnostics.AddError(
@@ -164,7 +184,7 @@ func (g *gwChannelResource) Create(ctx context.Context, request resource.CreateR
 		return
 	}
 
-	result, diags := g.convertFlespiChannelToResourceModel(ctx, channelInstance)
+	result, diags := g.convertFlespiChannelToResourceModel(ctx, channelInstance, data.Metadata, data.PreserveUnmanagedMetadata)
 
 	response.Diagnostics.Append(diags...)
 	response.Diagnostics.Append(response.State.Set(ctx, &result)...)
@@ -180,7 +200,13 @@ func (g *gwChannelResource) Read(ctx context.Context, request resource.ReadReque
 		return
 	}
 
-	channelInstance, err := g.client.Get(state.Id.ValueInt64())
+	channelInstance, err := getChannel(common.ForAccount(g.provider, state.AccountId.ValueInt64()), state.Id.ValueInt64())
+
+	if common.IsNotFound(err) {
+		tflog.Warn(ctx, "Flespi channel not found, removing from state", map[string]interface{}{"id": state.Id.ValueInt64()})
+		response.State.RemoveResource(ctx)
+		return
+	}
 
 	if err != nil {
 		response.Diagnostics.AddError(
@@ -190,7 +216,7 @@ func (g *gwChannelResource) Read(ctx context.Context, request resource.ReadReque
 		return
 	}
 
-	result, diags := g.convertFlespiChannelToResourceModel(ctx, channelInstance)
+	result, diags := g.convertFlespiChannelToResourceModel(ctx, channelInstance, state.Metadata, state.PreserveUnmanagedMetadata)
 
 	response.Diagnostics.Append(diags...)
 	response.Diagnostics.Append(response.State.Set(ctx, &result)...)
@@ -217,7 +243,42 @@ func (g *gwChannelResource) Update(ctx context.Context, request resource.UpdateR
 		return
 	}
 
-	_, err := g.client.Update(channelInstance)
+	channelInstance.AccountId = state.AccountId.ValueInt64()
+
+	planned, plannedKnown, diags := common.MetadataElements(ctx, plan.Metadata)
+	response.Diagnostics.Append(diags...)
+
+	prior, _, diags := common.MetadataElements(ctx, state.Metadata)
+	response.Diagnostics.Append(diags...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	var metadata *map[string]string
+
+	if plannedKnown {
+		var remote map[string]string
+
+		if plan.PreserveUnmanagedMetadata.ValueBool() {
+			current, err := getChannel(common.ForAccount(g.provider, state.AccountId.ValueInt64()), state.Id.ValueInt64())
+
+			if err != nil {
+				response.Diagnostics.AddError(
+					"Failed to read channel",
+					fmt.Sprintf("Error reading current channel metadata: %s", err),
+				)
+				return
+			}
+
+			remote = current.Metadata
+		}
+
+		merged := common.MetadataToSend(planned, prior, remote, plan.PreserveUnmanagedMetadata.ValueBool())
+		metadata = &merged
+	}
+
+	_, err := updateChannel(g.provider, channelInstance, metadata)
 
 	if err != nil {
 		response.Diagnostics.AddError(
@@ -227,7 +288,7 @@ func (g *gwChannelResource) Update(ctx context.Context, request resource.UpdateR
 		return
 	}
 
-	updatedChannel, err := g.client.Get(state.Id.ValueInt64())
+	updatedChannel, err := getChannel(common.ForAccount(g.provider, state.AccountId.ValueInt64()), state.Id.ValueInt64())
 	if err != nil {
 		response.Diagnostics.AddError(
 			"Failed to read updated channel",
@@ -236,7 +297,7 @@ func (g *gwChannelResource) Update(ctx context.Context, request resource.UpdateR
 		return
 	}
 
-	result, diags := g.convertFlespiChannelToResourceModel(ctx, updatedChannel)
+	result, diags := g.convertFlespiChannelToResourceModel(ctx, updatedChannel, common.OwnedMetadata(plan.Metadata, state.Metadata), plan.PreserveUnmanagedMetadata)
 
 	response.Diagnostics.Append(diags...)
 	response.Diagnostics.Append(response.State.Set(ctx, &result)...)
@@ -252,9 +313,9 @@ func (g *gwChannelResource) Delete(ctx context.Context, req resource.DeleteReque
 		return
 	}
 
-	err := g.client.DeleteById(data.Id.ValueInt64())
+	err := g.clientFor(data.AccountId).DeleteById(data.Id.ValueInt64())
 
-	if err != nil {
+	if err != nil && !common.IsNotFound(err) {
 		resp.Diagnostics.AddError(
 			"Failed to delete channel",
 			fmt.Sprintf("Error deleting channel: %s", err),
@@ -263,6 +324,75 @@ func (g *gwChannelResource) Delete(ctx context.Context, req resource.DeleteReque
 	}
 }
 
+func (g *gwChannelResource) ImportState(ctx context.Context, request resource.ImportStateRequest, response *resource.ImportStateResponse) {
+	common.ImportStateByAccountScopedId(ctx, request, response)
+}
+
+// ModifyPlan checks configuration against the configuration schema of the
+// channel protocol, so invalid fields are reported by plan instead of failing
+// the apply after other resources were already created.
+func (g *gwChannelResource) ModifyPlan(ctx context.Context, request resource.ModifyPlanRequest, response *resource.ModifyPlanResponse) {
+	if request.Plan.Raw.IsNull() || g.provider == nil {
+		return
+	}
+
+	var plan channelResourceModel
+
+	response.Diagnostics.Append(request.Plan.Get(ctx, &plan)...)
+
+	if response.Diagnostics.HasError() || plan.Configuration.IsNull() || plan.Configuration.IsUnknown() {
+		return
+	}
+
+	protocolId, protocolName := plan.ProtocolId, plan.ProtocolName
+
+	if !request.State.Raw.IsNull() {
+		var state channelResourceModel
+
+		response.Diagnostics.Append(request.State.Get(ctx, &state)...)
+
+		// protocol_id and protocol_name are computed, so they are unknown in
+		// the plan unless set in configuration.
+		if protocolId.IsUnknown() && protocolName.IsUnknown() {
+			protocolId = state.ProtocolId
+		}
+	}
+
+	catalog := catalogFor(g.provider)
+
+	if protocolId.IsNull() || protocolId.IsUnknown() {
+		if protocolName.IsNull() || protocolName.IsUnknown() {
+			return
+		}
+
+		id, err := catalog.channelProtocolId(protocolName.ValueString())
+
+		if err != nil {
+			response.Diagnostics.AddAttributeWarning(
+				path.Root("protocol_name"),
+				"Unable to Validate Channel Configuration",
+				"Could not resolve the channel protocol: "+err.Error(),
+			)
+			return
+		}
+
+		protocolId = types.Int64Value(id)
+	}
+
+	configSchema, err := catalog.channelSchema(protocolId.ValueInt64())
+
+	if err != nil {
+		response.Diagnostics.AddAttributeWarning(
+			path.Root("configuration"),
+			"Unable to Validate Channel Configuration",
+			fmt.Sprintf("Could not fetch the configuration schema of channel protocol %d: %s", protocolId.ValueInt64(), err),
+		)
+		return
+	}
+
+	validateConfiguration(configSchema, plan.Configuration, fmt.Sprintf("channel protocol %d", protocolId.ValueInt64()), &response.Diagnostics)
+}
+
 func (g *gwChannelResource) convertResourceModelToFlespiChannel(ctx context.Context, data channelResourceModel) (flespi_channel.Channel, diag.Diagnostics) {
 	var configuration map[string]interface{}
 	metadata := map[string]string{}
@@ -295,7 +425,7 @@ func (g *gwChannelResource) convertResourceModelToFlespiChannel(ctx context.Cont
 	}, nil
 }
 
-func (g *gwChannelResource) convertFlespiChannelToResourceModel(ctx context.Context, channel *flespi_channel.Channel) (*channelResourceModel, diag.Diagnostics) {
+func (g *gwChannelResource) convertFlespiChannelToResourceModel(ctx context.Context, channel *flespi_channel.Channel, managed types.Map, preserveUnmanaged types.Bool) (*channelResourceModel, diag.Diagnostics) {
 	var data channelResourceModel
 
 	data.Id = types.Int64Value(channel.Id)
@@ -317,12 +447,13 @@ func (g *gwChannelResource) convertFlespiChannelToResourceModel(ctx context.Cont
 
 	data.Configuration = jsontypes.NewNormalizedValue(string(configuration))
 
-	metadata, diags := types.MapValueFrom(ctx, types.StringType, channel.Metadata)
+	metadata, diags := common.MetadataValue(ctx, channel.Metadata, managed, preserveUnmanaged.ValueBool())
 	if diags.HasError() {
 		return nil, diags
 	}
 
 	data.Metadata = metadata
+	data.PreserveUnmanagedMetadata = types.BoolValue(preserveUnmanaged.ValueBool())
 	data.AccountId = types.Int64Value(channel.AccountId)
 
 	return &data, nil
@@ -0,0 +1,70 @@
+package gateway
+
+import (
+	"fmt"
+	"terraform-provider-flespi/internal/provider/resources/common"
+
+	flespi "github.com/mixser/flespi-client"
+	flespi_channel "github.com/mixser/flespi-client/resources/gateway/channel"
+)
+
+type channelsResponse struct {
+	Channels []flespi_channel.Channel `json:"result"`
+}
+
+const channelFields = "id,name,protocol_id,protocol_name,messages_ttl,enabled,configuration,metadata,cid"
+
+// getChannel reads a channel like flespi_channel.GetChannel does, but reports
+// an empty result as a 404 instead of indexing past the end of it.
+func getChannel(client *flespi.Client, channelId int64) (*flespi_channel.Channel, error) {
+	response := channelsResponse{}
+
+	endpoint := fmt.Sprintf("gw/channels/%d?fields=%s", channelId, channelFields)
+
+	if err := client.RequestAPI("GET", endpoint, nil, &response); err != nil {
+		return nil, err
+	}
+
+	if len(response.Channels) == 0 {
+		return nil, &flespi.APIError{StatusCode: 404, Method: "GET", Endpoint: endpoint, Message: "channel not found"}
+	}
+
+	return &response.Channels[0], nil
+}
+
+// channelUpdate is the body of a channel update. Metadata is a pointer so that
+// a nil value leaves the stored metadata alone while an empty map clears it;
+// the client library's map is dropped from the request when it is empty.
+type channelUpdate struct {
+	Name          string                 `json:"name"`
+	ProtocolId    int64                  `json:"protocol_id,omitempty"`
+	Enabled       bool                   `json:"enabled"`
+	MessagesTTL   int64                  `json:"messages_ttl,omitempty"`
+	Configuration map[string]interface{} `json:"configuration,omitempty"`
+	Metadata      *map[string]string     `json:"metadata,omitempty"`
+}
+
+func updateChannel(client *flespi.Client, channel flespi_channel.Channel, metadata *map[string]string) (*flespi_channel.Channel, error) {
+	headers := common.AccountHeaders(channel.AccountId)
+
+	update := channelUpdate{
+		Name:          channel.Name,
+		ProtocolId:    channel.ProtocolId,
+		Enabled:       channel.Enabled,
+		MessagesTTL:   channel.MessagesTTL,
+		Configuration: channel.Configuration,
+		Metadata:      metadata,
+	}
+
+	response := channelsResponse{}
+
+	if err := client.RequestAPIWithHeaders("PUT", fmt.Sprintf("gw/channels/%d", channel.Id), headers, update, &response); err != nil {
+		return nil, err
+	}
+
+	if len(response.Channels) == 0 {
+		return nil, fmt.Errorf("flespi returned no channel")
+	}
+
+	return &response.Channels[0], nil
+}
@@ -0,0 +1,339 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"terraform-provider-flespi/internal/provider/resources/common"
+
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	flespi "github.com/mixser/flespi-client"
+	flespi_channel "github.com/mixser/flespi-client/resources/gateway/channel"
+)
+
+var (
+	_ datasource.DataSource                     = &gwChannelDataSource{}
+	_ datasource.DataSourceWithConfigure        = &gwChannelDataSource{}
+	_ datasource.DataSourceWithConfigValidators = &gwChannelDataSource{}
+	_ datasource.DataSource                     = &gwChannelsDataSource{}
+	_ datasource.DataSourceWithConfigure        = &gwChannelsDataSource{}
+)
+
+type gwChannelDataSource struct {
+	provider *flespi.Client
+}
+
+type gwChannelsDataSource struct {
+	provider *flespi.Client
+}
+
+type channelDataSourceModel struct {
+	Id            types.Int64          `tfsdk:"id"`
+	Name          types.String         `tfsdk:"name"`
+	Enabled       types.Bool           `tfsdk:"enabled"`
+	ProtocolId    types.Int64          `tfsdk:"protocol_id"`
+	ProtocolName  types.String         `tfsdk:"protocol_name"`
+	MessagesTTL   types.Int64          `tfsdk:"messages_ttl"`
+	Configuration jsontypes.Normalized `tfsdk:"configuration"`
+	Metadata      types.Map            `tfsdk:"metadata"`
+	AccountId     types.Int64          `tfsdk:"account_id"`
+}
+
+type channelsDataSourceModel struct {
+	NameRegex     types.String             `tfsdk:"name_regex"`
+	AccountId     types.Int64              `tfsdk:"account_id"`
+	Enabled       types.Bool               `tfsdk:"enabled"`
+	MetadataKey   types.String             `tfsdk:"metadata_key"`
+	MetadataValue types.String             `tfsdk:"metadata_value"`
+	Channels      []channelDataSourceModel `tfsdk:"channels"`
+}
+
+func NewChannelDataSource() datasource.DataSource {
+	return &gwChannelDataSource{}
+}
+
+func NewChannelsDataSource() datasource.DataSource {
+	return &gwChannelsDataSource{}
+}
+
+func channelDataSourceAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"id": schema.Int64Attribute{
+			Computed: true,
+		},
+		"name": schema.StringAttribute{
+			Computed: true,
+		},
+		"enabled": schema.BoolAttribute{
+			Computed: true,
+		},
+		"protocol_id": schema.Int64Attribute{
+			Computed: true,
+		},
+		"protocol_name": schema.StringAttribute{
+			Computed: true,
+		},
+		"messages_ttl": schema.Int64Attribute{
+			Computed: true,
+		},
+		"configuration": schema.StringAttribute{
+			Computed:   true,
+			CustomType: jsontypes.NormalizedType{},
+		},
+		"metadata": schema.MapAttribute{
+			Computed:    true,
+			ElementType: types.StringType,
+		},
+		"account_id": schema.Int64Attribute{
+			Computed: true,
+		},
+	}
+}
+
+func (d *gwChannelDataSource) Metadata(ctx context.Context, request datasource.MetadataRequest, response *datasource.MetadataResponse) {
+	response.TypeName = request.ProviderTypeName + "_channel"
+}
+
+func (d *gwChannelDataSource) Configure(ctx context.Context, request datasource.ConfigureRequest, response *datasource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	client, ok := request.ProviderData.(*flespi.Client)
+
+	if !ok {
+		response.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *flespi.Client, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+		)
+
+		return
+	}
+
+	d.provider = client
+}
+
+// clientFor returns a channel client acting as accountId, or as the provider account when it is null.
+func (d *gwChannelDataSource) clientFor(accountId types.Int64) *flespi_channel.ChannelClient {
+	return common.ForAccount(d.provider, accountId.ValueInt64()).Channels
+}
+
+func (d *gwChannelDataSource) Schema(ctx context.Context, request datasource.SchemaRequest, response *datasource.SchemaResponse) {
+	attributes := channelDataSourceAttributes()
+
+	attributes["id"] = schema.Int64Attribute{
+		Optional:    true,
+		Computed:    true,
+		Description: "ID of the channel to look up.",
+	}
+	attributes["name"] = schema.StringAttribute{
+		Optional:    true,
+		Computed:    true,
+		Description: "Exact name of the channel to look up.",
+	}
+
+	attributes["account_id"] = schema.Int64Attribute{
+		Optional:    true,
+		Computed:    true,
+		Description: "(Sub)account to look the channel up in. Defaults to the provider's account_id.",
+	}
+
+	response.Schema = schema.Schema{
+		Description: "Looks up a single flespi channel by id or exact name.",
+		Attributes:  attributes,
+	}
+}
+
+func (d *gwChannelDataSource) ConfigValidators(ctx context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		datasourcevalidator.ExactlyOneOf(
+			path.MatchRoot("id"),
+			path.MatchRoot("name"),
+		),
+	}
+}
+
+func (d *gwChannelDataSource) Read(ctx context.Context, request datasource.ReadRequest, response *datasource.ReadResponse) {
+	var config channelDataSourceModel
+
+	response.Diagnostics.Append(request.Config.Get(ctx, &config)...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	client := d.clientFor(config.AccountId)
+
+	var channel *flespi_channel.Channel
+	var err error
+
+	if !config.Id.IsNull() {
+		channel, err = getChannel(common.ForAccount(d.provider, config.AccountId.ValueInt64()), config.Id.ValueInt64())
+	} else {
+		var channels []flespi_channel.Channel
+
+		channels, err = client.List()
+
+		if err == nil {
+			channel, err = common.FindByName(channels, config.Name.ValueString(),
+				func(item flespi_channel.Channel) string { return item.Name },
+				func(item flespi_channel.Channel) int64 { return item.Id },
+			)
+		}
+	}
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error Reading Flespi Channel",
+			"Could not look up Flespi channel: "+err.Error(),
+		)
+		return
+	}
+
+	model, diags := convertFlespiChannelToDataSourceModel(ctx, channel)
+
+	response.Diagnostics.Append(diags...)
+	response.Diagnostics.Append(response.State.Set(ctx, model)...)
+}
+
+func (d *gwChannelsDataSource) Metadata(ctx context.Context, request datasource.MetadataRequest, response *datasource.MetadataResponse) {
+	response.TypeName = request.ProviderTypeName + "_channels"
+}
+
+func (d *gwChannelsDataSource) Configure(ctx context.Context, request datasource.ConfigureRequest, response *datasource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	client, ok := request.ProviderData.(*flespi.Client)
+
+	if !ok {
+		response.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *flespi.Client, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+		)
+
+		return
+	}
+
+	d.provider = client
+}
+
+// clientFor returns a channel client acting as accountId, or as the provider account when it is null.
+func (d *gwChannelsDataSource) clientFor(accountId types.Int64) *flespi_channel.ChannelClient {
+	return common.ForAccount(d.provider, accountId.ValueInt64()).Channels
+}
+
+func (d *gwChannelsDataSource) Schema(ctx context.Context, request datasource.SchemaRequest, response *datasource.SchemaResponse) {
+	response.Schema = schema.Schema{
+		Description: "Lists flespi channels matching all of the given filters.",
+		Attributes: map[string]schema.Attribute{
+			"name_regex": schema.StringAttribute{
+				Optional:    true,
+				Description: "Regular expression the channel name must match.",
+			},
+			"account_id": schema.Int64Attribute{
+				Optional:    true,
+				Description: "List channels as this (sub)account and only return the ones it owns.",
+			},
+			"enabled": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Only return enabled (true) or disabled (false) channels.",
+			},
+			"metadata_key": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only return channels having this metadata key.",
+			},
+			"metadata_value": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only return channels whose metadata_key has this value.",
+			},
+			"channels": schema.ListNestedAttribute{
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: channelDataSourceAttributes(),
+				},
+			},
+		},
+	}
+}
+
+func (d *gwChannelsDataSource) Read(ctx context.Context, request datasource.ReadRequest, response *datasource.ReadResponse) {
+	var config channelsDataSourceModel
+
+	response.Diagnostics.Append(request.Config.Get(ctx, &config)...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	filter, diags := common.NewListFilter(config.NameRegex, config.AccountId, config.Enabled, config.MetadataKey, config.MetadataValue)
+
+	response.Diagnostics.Append(diags...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	channels, err := d.clientFor(config.AccountId).List()
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error Reading Flespi Channels",
+			"Could not list Flespi channels: "+err.Error(),
+		)
+		return
+	}
+
+	config.Channels = []channelDataSourceModel{}
+
+	for _, channel := range channels {
+		if !filter.MatchName(channel.Name) || !filter.MatchAccountId(channel.AccountId) ||
+			!filter.MatchEnabled(channel.Enabled) || !filter.MatchMetadata(channel.Metadata) {
+			continue
+		}
+
+		model, diags := convertFlespiChannelToDataSourceModel(ctx, &channel)
+
+		response.Diagnostics.Append(diags...)
+
+		if response.Diagnostics.HasError() {
+			return
+		}
+
+		config.Channels = append(config.Channels, *model)
+	}
+
+	response.Diagnostics.Append(response.State.Set(ctx, &config)...)
+}
+
+func convertFlespiChannelToDataSourceModel(ctx context.Context, channel *flespi_channel.Channel) (*channelDataSourceModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	configuration, err := json.Marshal(channel.Configuration)
+	if err != nil {
+		diags.AddError("Unable to marshal configuration", err.Error())
+		return nil, diags
+	}
+
+	metadata, metaDiags := types.MapValueFrom(ctx, types.StringType, channel.Metadata)
+	diags.Append(metaDiags...)
+
+	return &channelDataSourceModel{
+		Id:            types.Int64Value(channel.Id),
+		Name:          types.StringValue(channel.Name),
+		Enabled:       types.BoolValue(channel.Enabled),
+		ProtocolId:    types.Int64Value(channel.ProtocolId),
+		ProtocolName:  types.StringValue(channel.ProtocolName),
+		MessagesTTL:   types.Int64Value(channel.MessagesTTL),
+		Configuration: jsontypes.NewNormalizedValue(string(configuration)),
+		Metadata:      metadata,
+		AccountId:     types.Int64Value(channel.AccountId),
+	}, diags
+}
@@ -0,0 +1,247 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-flespi/internal/provider/resources/common"
+
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	flespi "github.com/mixser/flespi-client"
+)
+
+var (
+	_ datasource.DataSource                     = &gwChannelProtocolDataSource{}
+	_ datasource.DataSourceWithConfigure        = &gwChannelProtocolDataSource{}
+	_ datasource.DataSourceWithConfigValidators = &gwChannelProtocolDataSource{}
+	_ datasource.DataSource                     = &gwChannelProtocolsDataSource{}
+	_ datasource.DataSourceWithConfigure        = &gwChannelProtocolsDataSource{}
+)
+
+type gwChannelProtocolDataSource struct {
+	client *flespi.Client
+}
+
+type gwChannelProtocolsDataSource struct {
+	client *flespi.Client
+}
+
+type channelProtocolDataSourceModel struct {
+	Id            types.Int64          `tfsdk:"id"`
+	Name          types.String         `tfsdk:"name"`
+	Title         types.String         `tfsdk:"title"`
+	Configuration jsontypes.Normalized `tfsdk:"configuration_schema"`
+}
+
+type channelProtocolsDataSourceModel struct {
+	NameRegex types.String                     `tfsdk:"name_regex"`
+	Protocols []channelProtocolDataSourceModel `tfsdk:"protocols"`
+}
+
+func NewChannelProtocolDataSource() datasource.DataSource {
+	return &gwChannelProtocolDataSource{}
+}
+
+func NewChannelProtocolsDataSource() datasource.DataSource {
+	return &gwChannelProtocolsDataSource{}
+}
+
+func channelProtocolDataSourceAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"id": schema.Int64Attribute{
+			Computed: true,
+		},
+		"name": schema.StringAttribute{
+			Computed: true,
+		},
+		"title": schema.StringAttribute{
+			Computed: true,
+		},
+		"configuration_schema": schema.StringAttribute{
+			Computed:    true,
+			CustomType:  jsontypes.NormalizedType{},
+			Description: "JSON schema of the channel configuration for this protocol.",
+		},
+	}
+}
+
+func (d *gwChannelProtocolDataSource) Metadata(ctx context.Context, request datasource.MetadataRequest, response *datasource.MetadataResponse) {
+	response.TypeName = request.ProviderTypeName + "_channel_protocol"
+}
+
+func (d *gwChannelProtocolDataSource) Configure(ctx context.Context, request datasource.ConfigureRequest, response *datasource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	client, ok := request.ProviderData.(*flespi.Client)
+
+	if !ok {
+		response.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *flespi.Client, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *gwChannelProtocolDataSource) Schema(ctx context.Context, request datasource.SchemaRequest, response *datasource.SchemaResponse) {
+	attributes := channelProtocolDataSourceAttributes()
+
+	attributes["id"] = schema.Int64Attribute{
+		Optional:    true,
+		Computed:    true,
+		Description: "ID of the channel protocol to look up.",
+	}
+	attributes["name"] = schema.StringAttribute{
+		Optional:    true,
+		Computed:    true,
+		Description: "Name of the channel protocol to look up (case-insensitive).",
+	}
+
+	response.Schema = schema.Schema{
+		Description: "Looks up a single flespi channel protocol by id or name.",
+		Attributes:  attributes,
+	}
+}
+
+func (d *gwChannelProtocolDataSource) ConfigValidators(ctx context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		datasourcevalidator.ExactlyOneOf(
+			path.MatchRoot("id"),
+			path.MatchRoot("name"),
+		),
+	}
+}
+
+func (d *gwChannelProtocolDataSource) Read(ctx context.Context, request datasource.ReadRequest, response *datasource.ReadResponse) {
+	var config channelProtocolDataSourceModel
+
+	response.Diagnostics.Append(request.Config.Get(ctx, &config)...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	var protocol *channelProtocol
+	var err error
+
+	if !config.Id.IsNull() {
+		protocol, err = getChannelProtocol(d.client, config.Id.ValueInt64())
+	} else {
+		var protocols []channelProtocol
+
+		protocols, err = listChannelProtocols(d.client)
+
+		if err == nil {
+			protocol, err = findChannelProtocol(protocols, config.Name.ValueString())
+		}
+	}
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error Reading Flespi Channel Protocol",
+			"Could not look up Flespi channel protocol: "+err.Error(),
+		)
+		return
+	}
+
+	response.Diagnostics.Append(response.State.Set(ctx, convertChannelProtocolToDataSourceModel(protocol))...)
+}
+
+func (d *gwChannelProtocolsDataSource) Metadata(ctx context.Context, request datasource.MetadataRequest, response *datasource.MetadataResponse) {
+	response.TypeName = request.ProviderTypeName + "_channel_protocols"
+}
+
+func (d *gwChannelProtocolsDataSource) Configure(ctx context.Context, request datasource.ConfigureRequest, response *datasource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	client, ok := request.ProviderData.(*flespi.Client)
+
+	if !ok {
+		response.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *flespi.Client, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *gwChannelProtocolsDataSource) Schema(ctx context.Context, request datasource.SchemaRequest, response *datasource.SchemaResponse) {
+	response.Schema = schema.Schema{
+		Description: "Lists flespi channel protocols.",
+		Attributes: map[string]schema.Attribute{
+			"name_regex": schema.StringAttribute{
+				Optional:    true,
+				Description: "Regular expression the protocol name must match.",
+			},
+			"protocols": schema.ListNestedAttribute{
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: channelProtocolDataSourceAttributes(),
+				},
+			},
+		},
+	}
+}
+
+func (d *gwChannelProtocolsDataSource) Read(ctx context.Context, request datasource.ReadRequest, response *datasource.ReadResponse) {
+	var config channelProtocolsDataSourceModel
+
+	response.Diagnostics.Append(request.Config.Get(ctx, &config)...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	filter, diags := common.NewListFilter(config.NameRegex, types.Int64Null(), types.BoolNull(), types.StringNull(), types.StringNull())
+
+	response.Diagnostics.Append(diags...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	protocols, err := listChannelProtocols(d.client)
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error Reading Flespi Channel Protocols",
+			"Could not list Flespi channel protocols: "+err.Error(),
+		)
+		return
+	}
+
+	config.Protocols = []channelProtocolDataSourceModel{}
+
+	for _, protocol := range protocols {
+		if !filter.MatchName(protocol.Name) {
+			continue
+		}
+
+		config.Protocols = append(config.Protocols, *convertChannelProtocolToDataSourceModel(&protocol))
+	}
+
+	response.Diagnostics.Append(response.State.Set(ctx, &config)...)
+}
+
+func convertChannelProtocolToDataSourceModel(protocol *channelProtocol) *channelProtocolDataSourceModel {
+	return &channelProtocolDataSourceModel{
+		Id:            types.Int64Value(protocol.Id),
+		Name:          types.StringValue(protocol.Name),
+		Title:         types.StringValue(protocol.Title),
+		Configuration: jsontypes.NewNormalizedValue(rawJSONString(protocol.Schema)),
+	}
+}
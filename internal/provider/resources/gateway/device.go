@@ -2,9 +2,11 @@ package gateway
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"terraform-provider-flespi/internal/provider/resources/common"
 
-	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -18,12 +20,15 @@ import (
 )
 
 var (
-	_ resource.Resource              = &gwDeviceResource{}
-	_ resource.ResourceWithConfigure = &gwDeviceResource{}
+	_ resource.Resource                 = &gwDeviceResource{}
+	_ resource.ResourceWithConfigure    = &gwDeviceResource{}
+	_ resource.ResourceWithImportState  = &gwDeviceResource{}
+	_ resource.ResourceWithUpgradeState = &gwDeviceResource{}
 )
 
 type gwDeviceResource struct {
-	client *flespi_device.DeviceClient
+	client   *flespi_device.DeviceClient
+	provider *flespi.Client
 }
 
 func NewDeviceResource() resource.Resource {
@@ -36,6 +41,29 @@ type deviceResourceModel struct {
 
 	Enabled types.Bool `tfsdk:"enabled"`
 
+	Configuration jsontypes.Normalized `tfsdk:"configuration"`
+
+	DeviceTypeId types.Int64 `tfsdk:"device_type_id"`
+
+	MessagesTTL    types.Int64 `tfsdk:"messages_ttl"`
+	MessagesRotate types.Int64 `tfsdk:"messages_rotate"`
+
+	MediaTTL    types.Int64 `tfsdk:"media_ttl"`
+	MediaRotate types.Int64 `tfsdk:"media_rotate"`
+
+	Metadata                  types.Map  `tfsdk:"metadata"`
+	PreserveUnmanagedMetadata types.Bool `tfsdk:"preserve_unmanaged_metadata"`
+
+	AccountId types.Int64 `tfsdk:"account_id"`
+}
+
+// deviceResourceModelV0 is the state layout before configuration became JSON.
+type deviceResourceModelV0 struct {
+	Id   types.Int64  `tfsdk:"id"`
+	Name types.String `tfsdk:"name"`
+
+	Enabled types.Bool `tfsdk:"enabled"`
+
 	Configuration types.Map `tfsdk:"configuration"`
 
 	DeviceTypeId types.Int64 `tfsdk:"device_type_id"`
@@ -70,10 +98,17 @@ func (g *gwDeviceResource) Configure(ctx context.Context, request resource.Confi
 	}
 
 	g.client = client.Devices
+	g.provider = client
+}
+
+// clientFor returns a device client acting as the (sub)account that owns the device.
+func (g *gwDeviceResource) clientFor(accountId types.Int64) *flespi_device.DeviceClient {
+	return common.ForAccount(g.provider, accountId.ValueInt64()).Devices
 }
 
 func (g *gwDeviceResource) Schema(ctx context.Context, request resource.SchemaRequest, response *resource.SchemaResponse) {
 	response.Schema = schema.Schema{
+		Version: 1,
 		Attributes: map[string]schema.Attribute{
 			"id": schema.Int64Attribute{
 				Computed: true,
@@ -110,11 +145,19 @@ func (g *gwDeviceResource) Schema(ctx context.Context, request resource.SchemaRe
 				Computed: true,
 				Default:  int64default.StaticInt64(0),
 			},
-			"configuration": schema.MapAttribute{
+			"configuration": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				CustomType:  jsontypes.NormalizedType{},
+				Description: "Device configuration as JSON, including nested settings such as ident lists or media options. The available fields are described by the configuration_schema of the flespi_device_type data source. Use jsonencode() in HCL.",
+			},
+			"metadata": schema.MapAttribute{
 				Optional:    true,
 				Computed:    true,
 				ElementType: types.StringType,
+				Description: "Device metadata. Left untouched when not set.",
 			},
+			"preserve_unmanaged_metadata": common.PreserveUnmanagedMetadataAttribute(),
 			"account_id": schema.Int64Attribute{
 				Optional:    true,
 				Computed:    true,
@@ -133,21 +176,21 @@ func (g *gwDeviceResource) Create(ctx context.Context, request resource.CreateRe
 		return
 	}
 
-	instance := g.convertResourceModelToFlespiDevice(ctx, *data)
+	instance := g.convertResourceModelToFlespiDevice(*data)
+
+	planned, plannedKnown, diags := common.MetadataElements(ctx, data.Metadata)
 
-	createOpts := []flespi_device.CreateDeviceOption{
-		flespi_device.WithMessage(instance.MessagesTTL, instance.MessagesRotate),
-		flespi_device.WithMedia(instance.MediaTTL, instance.MediaRotate),
-		flespi_device.WithConfiguration(instance.Configuration),
-		flespi_device.WithAccountId(instance.AccountId),
+	response.Diagnostics.Append(diags...)
+
+	if response.Diagnostics.HasError() {
+		return
 	}
 
-	deviceInstance, err := g.client.Create(
-		instance.Name,
-		instance.Enabled,
-		instance.DeviceTypeId,
-		createOpts...,
-	)
+	if plannedKnown {
+		instance.Metadata = &planned
+	}
+
+	deviceInstance, err := createDevice(g.provider, instance)
 
 	if err != nil {
 		response.Diagnostics.AddError(
@@ -157,7 +200,7 @@ func (g *gwDeviceResource) Create(ctx context.Context, request resource.CreateRe
 		return
 	}
 
-	deviceInstance, err = g.client.Get(deviceInstance.Id)
+	deviceInstance, err = getDevice(common.ForAccount(g.provider, data.AccountId.ValueInt64()), deviceInstance.Id)
 
 	if err != nil {
 		response.Diagnostics.AddError(
@@ -167,7 +210,7 @@ func (g *gwDeviceResource) Create(ctx context.Context, request resource.CreateRe
 		return
 	}
 
-	result, diags := g.convertFlespiDeviceToResourceModel(deviceInstance)
+	result, diags := g.convertFlespiDeviceToResourceModel(ctx, deviceInstance, data.Configuration, data.Metadata, data.PreserveUnmanagedMetadata)
 
 	response.Diagnostics.Append(diags...)
 
@@ -184,7 +227,13 @@ func (g *gwDeviceResource) Read(ctx context.Context, request resource.ReadReques
 		return
 	}
 
-	device, err := g.client.Get(state.Id.ValueInt64())
+	device, err := getDevice(common.ForAccount(g.provider, state.AccountId.ValueInt64()), state.Id.ValueInt64())
+
+	if common.IsNotFound(err) {
+		tflog.Warn(ctx, "Flespi device not found, removing from state", map[string]interface{}{"id": state.Id.ValueInt64()})
+		response.State.RemoveResource(ctx)
+		return
+	}
 
 	if err != nil {
 		response.Diagnostics.AddError(
@@ -195,7 +244,7 @@ func (g *gwDeviceResource) Read(ctx context.Context, request resource.ReadReques
 		return
 	}
 
-	model, diags := g.convertFlespiDeviceToResourceModel(device)
+	model, diags := g.convertFlespiDeviceToResourceModel(ctx, device, state.Configuration, state.Metadata, state.PreserveUnmanagedMetadata)
 
 	response.Diagnostics.Append(diags...)
 	response.Diagnostics.Append(response.State.Set(ctx, model)...)
@@ -214,9 +263,42 @@ func (g *gwDeviceResource) Update(ctx context.Context, request resource.UpdateRe
 
 	plan.Id = state.Id
 
-	var device = g.convertResourceModelToFlespiDevice(ctx, plan)
+	var device = g.convertResourceModelToFlespiDevice(plan)
+
+	device.AccountId = state.AccountId.ValueInt64()
+
+	planned, plannedKnown, diags := common.MetadataElements(ctx, plan.Metadata)
+	response.Diagnostics.Append(diags...)
+
+	prior, _, diags := common.MetadataElements(ctx, state.Metadata)
+	response.Diagnostics.Append(diags...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	if plannedKnown {
+		var remote map[string]string
+
+		if plan.PreserveUnmanagedMetadata.ValueBool() {
+			current, err := getDevice(common.ForAccount(g.provider, state.AccountId.ValueInt64()), state.Id.ValueInt64())
 
-	_, err := g.client.Update(device)
+			if err != nil {
+				response.Diagnostics.AddError(
+					"Error Reading Flespi Device",
+					"Could not read current device metadata: "+err.Error(),
+				)
+				return
+			}
+
+			remote = current.metadata()
+		}
+
+		metadata := common.MetadataToSend(planned, prior, remote, plan.PreserveUnmanagedMetadata.ValueBool())
+		device.Metadata = &metadata
+	}
+
+	_, err := updateDevice(g.provider, device)
 
 	if err != nil {
 		response.Diagnostics.AddError(
@@ -226,16 +308,17 @@ func (g *gwDeviceResource) Update(ctx context.Context, request resource.UpdateRe
 		return
 	}
 
-	updatedDevice, err := g.client.Get(state.Id.ValueInt64())
+	updatedDevice, err := getDevice(common.ForAccount(g.provider, state.AccountId.ValueInt64()), state.Id.ValueInt64())
 
 	if err != nil {
 		response.Diagnostics.AddError(
 			"Error Reading Flespi Device",
 			"Could not read device Id: "+state.Id.String()+": "+err.Error(),
 		)
+		return
 	}
 
-	model, diags := g.convertFlespiDeviceToResourceModel(updatedDevice)
+	model, diags := g.convertFlespiDeviceToResourceModel(ctx, updatedDevice, plan.Configuration, common.OwnedMetadata(plan.Metadata, state.Metadata), plan.PreserveUnmanagedMetadata)
 
 	response.Diagnostics.Append(diags...)
 	response.Diagnostics.Append(response.State.Set(ctx, model)...)
@@ -252,9 +335,9 @@ func (g *gwDeviceResource) Delete(ctx context.Context, request resource.DeleteRe
 		return
 	}
 
-	err := g.client.DeleteById(state.Id.ValueInt64())
+	err := g.clientFor(state.AccountId).DeleteById(state.Id.ValueInt64())
 
-	if err != nil {
+	if err != nil && !common.IsNotFound(err) {
 		response.Diagnostics.AddError(
 			"Error Deleting Flespi Device",
 			"Could not delete device, unexpected error: "+err.Error(),
@@ -263,18 +346,73 @@ func (g *gwDeviceResource) Delete(ctx context.Context, request resource.DeleteRe
 	}
 }
 
-func (g *gwDeviceResource) convertResourceModelToFlespiDevice(ctx context.Context, data deviceResourceModel) flespi_device.Device {
-	configuration := make(map[string]string)
+func (g *gwDeviceResource) ImportState(ctx context.Context, request resource.ImportStateRequest, response *resource.ImportStateResponse) {
+	common.ImportStateByAccountScopedId(ctx, request, response)
+}
 
-	for key, value := range data.Configuration.Elements() {
-		if strValue, ok := value.(types.String); ok {
-			configuration[key] = strValue.ValueString()
-		} else {
-			tflog.Error(ctx, fmt.Sprintf("expected types.String, got %T (skip attribute)", value))
-		}
+func (g *gwDeviceResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		// Version 0 stored configuration as a map of strings.
+		0: {
+			PriorSchema: &schema.Schema{
+				Attributes: map[string]schema.Attribute{
+					"id":              schema.Int64Attribute{Computed: true},
+					"name":            schema.StringAttribute{Required: true},
+					"enabled":         schema.BoolAttribute{Required: true},
+					"device_type_id":  schema.Int64Attribute{Required: true},
+					"messages_ttl":    schema.Int64Attribute{Optional: true, Computed: true},
+					"messages_rotate": schema.Int64Attribute{Optional: true, Computed: true},
+					"media_ttl":       schema.Int64Attribute{Optional: true, Computed: true},
+					"media_rotate":    schema.Int64Attribute{Optional: true, Computed: true},
+					"configuration":   schema.MapAttribute{Optional: true, Computed: true, ElementType: types.StringType},
+					"account_id":      schema.Int64Attribute{Optional: true, Computed: true},
+				},
+			},
+			StateUpgrader: func(ctx context.Context, request resource.UpgradeStateRequest, response *resource.UpgradeStateResponse) {
+				var prior deviceResourceModelV0
+
+				response.Diagnostics.Append(request.State.Get(ctx, &prior)...)
+
+				if response.Diagnostics.HasError() {
+					return
+				}
+
+				configuration, diags := mapToNormalizedJSON(ctx, prior.Configuration)
+
+				response.Diagnostics.Append(diags...)
+
+				if response.Diagnostics.HasError() {
+					return
+				}
+
+				response.Diagnostics.Append(response.State.Set(ctx, deviceResourceModel{
+					Id:             prior.Id,
+					Name:           prior.Name,
+					Enabled:        prior.Enabled,
+					Configuration:  configuration,
+					DeviceTypeId:   prior.DeviceTypeId,
+					MessagesTTL:    prior.MessagesTTL,
+					MessagesRotate: prior.MessagesRotate,
+					MediaTTL:       prior.MediaTTL,
+					MediaRotate:    prior.MediaRotate,
+					Metadata:       types.MapNull(types.StringType),
+					AccountId:      prior.AccountId,
+
+					PreserveUnmanagedMetadata: types.BoolValue(false),
+				})...)
+			},
+		},
 	}
+}
 
-	return flespi_device.Device{
+func (g *gwDeviceResource) convertResourceModelToFlespiDevice(data deviceResourceModel) deviceObject {
+	var configuration json.RawMessage
+
+	if !data.Configuration.IsNull() && !data.Configuration.IsUnknown() {
+		configuration = json.RawMessage(data.Configuration.ValueString())
+	}
+
+	return deviceObject{
 		Id:             data.Id.ValueInt64(),
 		Name:           data.Name.ValueString(),
 		Enabled:        data.Enabled.ValueBool(),
@@ -284,12 +422,15 @@ func (g *gwDeviceResource) convertResourceModelToFlespiDevice(ctx context.Contex
 		MediaTTL:       data.MediaTTL.ValueInt64(),
 		MediaRotate:    data.MediaRotate.ValueInt64(),
 		Configuration:  configuration,
-		Metadata:       map[string]string{},
 		AccountId:      data.AccountId.ValueInt64(),
 	}
 }
 
-func (g *gwDeviceResource) convertFlespiDeviceToResourceModel(device *flespi_device.Device) (*deviceResourceModel, diag.Diagnostics) {
+// convertFlespiDeviceToResourceModel builds the state of device. configured is
+// the configuration Terraform last wrote, kept as is when flespi only added
+// defaults to it. managed is the metadata Terraform owns, which limits the
+// metadata kept in state when preserveUnmanaged is set.
+func (g *gwDeviceResource) convertFlespiDeviceToResourceModel(ctx context.Context, device *deviceObject, configured jsontypes.Normalized, managed types.Map, preserveUnmanaged types.Bool) (*deviceResourceModel, diag.Diagnostics) {
 	var state deviceResourceModel
 
 	state.Id = types.Int64Value(device.Id)
@@ -304,14 +445,11 @@ func (g *gwDeviceResource) convertFlespiDeviceToResourceModel(device *flespi_dev
 	state.MediaTTL = types.Int64Value(device.MediaTTL)
 	state.MediaRotate = types.Int64Value(device.MediaRotate)
 
-	configuration := make(map[string]attr.Value)
-
-	for key, value := range device.Configuration {
-		configuration[key] = types.StringValue(value)
-	}
+	state.Configuration = common.PreferConfiguredJSON(configured, rawJSONString(device.Configuration))
 
-	cfg, diags := types.MapValue(types.StringType, configuration)
-	state.Configuration = cfg
+	metadata, diags := common.MetadataValue(ctx, device.metadata(), managed, preserveUnmanaged.ValueBool())
+	state.Metadata = metadata
+	state.PreserveUnmanagedMetadata = types.BoolValue(preserveUnmanaged.ValueBool())
 
 	state.AccountId = types.Int64Value(device.AccountId)
 
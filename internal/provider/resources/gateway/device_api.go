@@ -0,0 +1,106 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"terraform-provider-flespi/internal/provider/resources/common"
+
+	flespi "github.com/mixser/flespi-client"
+)
+
+// deviceObject mirrors flespi_device.Device but keeps the configuration as raw
+// JSON, so nested settings (ident lists, settings_polling, media options) and
+// non-string values round-trip exactly. Retention settings are always sent, so
+// setting one back to zero reaches flespi. Metadata is a pointer so that a nil
+// value leaves the stored metadata alone while an empty map clears it.
+type deviceObject struct {
+	Id             int64              `json:"id,omitempty"`
+	Name           string             `json:"name"`
+	Enabled        bool               `json:"enabled"`
+	Configuration  json.RawMessage    `json:"configuration,omitempty"`
+	DeviceTypeId   int64              `json:"device_type_id"`
+	MessagesTTL    int64              `json:"messages_ttl"`
+	MessagesRotate int64              `json:"messages_rotate"`
+	MediaTTL       int64              `json:"media_ttl"`
+	MediaRotate    int64              `json:"media_rotate"`
+	Metadata       *map[string]string `json:"metadata,omitempty"`
+	AccountId      int64              `json:"cid,omitempty"`
+}
+
+// metadata returns the device metadata as read from the API.
+func (d deviceObject) metadata() map[string]string {
+	if d.Metadata == nil {
+		return nil
+	}
+
+	return *d.Metadata
+}
+
+type devicesResponse struct {
+	Devices []deviceObject `json:"result"`
+}
+
+const deviceFields = "id,name,enabled,device_type_id,messages_ttl,messages_rotate,media_ttl,media_rotate,configuration,metadata,cid"
+
+func createDevice(client *flespi.Client, device deviceObject) (*deviceObject, error) {
+	headers := common.AccountHeaders(device.AccountId)
+	device.AccountId = 0
+
+	response := devicesResponse{}
+
+	if err := client.RequestAPIWithHeaders("POST", "gw/devices", headers, []deviceObject{device}, &response); err != nil {
+		return nil, err
+	}
+
+	if len(response.Devices) == 0 {
+		return nil, fmt.Errorf("flespi returned no device")
+	}
+
+	return &response.Devices[0], nil
+}
+
+func getDevice(client *flespi.Client, deviceId int64) (*deviceObject, error) {
+	response := devicesResponse{}
+
+	endpoint := fmt.Sprintf("gw/devices/%d?fields=%s", deviceId, deviceFields)
+
+	if err := client.RequestAPI("GET", endpoint, nil, &response); err != nil {
+		return nil, err
+	}
+
+	if len(response.Devices) == 0 {
+		return nil, &flespi.APIError{StatusCode: 404, Method: "GET", Endpoint: endpoint, Message: "device not found"}
+	}
+
+	return &response.Devices[0], nil
+}
+
+func listDevices(client *flespi.Client) ([]deviceObject, error) {
+	response := devicesResponse{}
+
+	if err := client.RequestAPI("GET", "gw/devices/all?fields="+deviceFields, nil, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Devices, nil
+}
+
+func updateDevice(client *flespi.Client, device deviceObject) (*deviceObject, error) {
+	deviceId := device.Id
+	headers := common.AccountHeaders(device.AccountId)
+
+	device.Id = 0
+	device.AccountId = 0
+
+	response := devicesResponse{}
+
+	if err := client.RequestAPIWithHeaders("PUT", fmt.Sprintf("gw/devices/%d", deviceId), headers, device, &response); err != nil {
+		return nil, err
+	}
+
+	if len(response.Devices) == 0 {
+		return nil, fmt.Errorf("flespi returned no device")
+	}
+
+	return &response.Devices[0], nil
+}
@@ -0,0 +1,331 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-flespi/internal/provider/resources/common"
+
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	flespi "github.com/mixser/flespi-client"
+)
+
+var (
+	_ datasource.DataSource                     = &gwDeviceDataSource{}
+	_ datasource.DataSourceWithConfigure        = &gwDeviceDataSource{}
+	_ datasource.DataSourceWithConfigValidators = &gwDeviceDataSource{}
+	_ datasource.DataSource                     = &gwDevicesDataSource{}
+	_ datasource.DataSourceWithConfigure        = &gwDevicesDataSource{}
+)
+
+type gwDeviceDataSource struct {
+	client *flespi.Client
+}
+
+type gwDevicesDataSource struct {
+	client *flespi.Client
+}
+
+type deviceDataSourceModel struct {
+	Id             types.Int64          `tfsdk:"id"`
+	Name           types.String         `tfsdk:"name"`
+	Enabled        types.Bool           `tfsdk:"enabled"`
+	DeviceTypeId   types.Int64          `tfsdk:"device_type_id"`
+	MessagesTTL    types.Int64          `tfsdk:"messages_ttl"`
+	MessagesRotate types.Int64          `tfsdk:"messages_rotate"`
+	MediaTTL       types.Int64          `tfsdk:"media_ttl"`
+	MediaRotate    types.Int64          `tfsdk:"media_rotate"`
+	Configuration  jsontypes.Normalized `tfsdk:"configuration"`
+	Metadata       types.Map            `tfsdk:"metadata"`
+	AccountId      types.Int64          `tfsdk:"account_id"`
+}
+
+type devicesDataSourceModel struct {
+	NameRegex     types.String            `tfsdk:"name_regex"`
+	AccountId     types.Int64             `tfsdk:"account_id"`
+	Enabled       types.Bool              `tfsdk:"enabled"`
+	MetadataKey   types.String            `tfsdk:"metadata_key"`
+	MetadataValue types.String            `tfsdk:"metadata_value"`
+	Devices       []deviceDataSourceModel `tfsdk:"devices"`
+}
+
+func NewDeviceDataSource() datasource.DataSource {
+	return &gwDeviceDataSource{}
+}
+
+func NewDevicesDataSource() datasource.DataSource {
+	return &gwDevicesDataSource{}
+}
+
+func deviceDataSourceAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"id": schema.Int64Attribute{
+			Computed: true,
+		},
+		"name": schema.StringAttribute{
+			Computed: true,
+		},
+		"enabled": schema.BoolAttribute{
+			Computed: true,
+		},
+		"device_type_id": schema.Int64Attribute{
+			Computed: true,
+		},
+		"messages_ttl": schema.Int64Attribute{
+			Computed: true,
+		},
+		"messages_rotate": schema.Int64Attribute{
+			Computed: true,
+		},
+		"media_ttl": schema.Int64Attribute{
+			Computed: true,
+		},
+		"media_rotate": schema.Int64Attribute{
+			Computed: true,
+		},
+		"configuration": schema.StringAttribute{
+			Computed:   true,
+			CustomType: jsontypes.NormalizedType{},
+		},
+		"metadata": schema.MapAttribute{
+			Computed:    true,
+			ElementType: types.StringType,
+		},
+		"account_id": schema.Int64Attribute{
+			Computed: true,
+		},
+	}
+}
+
+func (d *gwDeviceDataSource) Metadata(ctx context.Context, request datasource.MetadataRequest, response *datasource.MetadataResponse) {
+	response.TypeName = request.ProviderTypeName + "_device"
+}
+
+func (d *gwDeviceDataSource) Configure(ctx context.Context, request datasource.ConfigureRequest, response *datasource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	client, ok := request.ProviderData.(*flespi.Client)
+
+	if !ok {
+		response.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *flespi.Client, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *gwDeviceDataSource) Schema(ctx context.Context, request datasource.SchemaRequest, response *datasource.SchemaResponse) {
+	attributes := deviceDataSourceAttributes()
+
+	attributes["id"] = schema.Int64Attribute{
+		Optional:    true,
+		Computed:    true,
+		Description: "ID of the device to look up.",
+	}
+	attributes["name"] = schema.StringAttribute{
+		Optional:    true,
+		Computed:    true,
+		Description: "Exact name of the device to look up.",
+	}
+
+	attributes["account_id"] = schema.Int64Attribute{
+		Optional:    true,
+		Computed:    true,
+		Description: "(Sub)account to look the device up in. Defaults to the provider's account_id.",
+	}
+
+	response.Schema = schema.Schema{
+		Description: "Looks up a single flespi device by id or exact name.",
+		Attributes:  attributes,
+	}
+}
+
+func (d *gwDeviceDataSource) ConfigValidators(ctx context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		datasourcevalidator.ExactlyOneOf(
+			path.MatchRoot("id"),
+			path.MatchRoot("name"),
+		),
+	}
+}
+
+func (d *gwDeviceDataSource) Read(ctx context.Context, request datasource.ReadRequest, response *datasource.ReadResponse) {
+	var config deviceDataSourceModel
+
+	response.Diagnostics.Append(request.Config.Get(ctx, &config)...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	client := common.ForAccount(d.client, config.AccountId.ValueInt64())
+
+	var device *deviceObject
+	var err error
+
+	if !config.Id.IsNull() {
+		device, err = getDevice(client, config.Id.ValueInt64())
+	} else {
+		var devices []deviceObject
+
+		devices, err = listDevices(client)
+
+		if err == nil {
+			device, err = common.FindByName(devices, config.Name.ValueString(),
+				func(item deviceObject) string { return item.Name },
+				func(item deviceObject) int64 { return item.Id },
+			)
+		}
+	}
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error Reading Flespi Device",
+			"Could not look up Flespi device: "+err.Error(),
+		)
+		return
+	}
+
+	model, diags := convertFlespiDeviceToDataSourceModel(ctx, device)
+
+	response.Diagnostics.Append(diags...)
+	response.Diagnostics.Append(response.State.Set(ctx, model)...)
+}
+
+func (d *gwDevicesDataSource) Metadata(ctx context.Context, request datasource.MetadataRequest, response *datasource.MetadataResponse) {
+	response.TypeName = request.ProviderTypeName + "_devices"
+}
+
+func (d *gwDevicesDataSource) Configure(ctx context.Context, request datasource.ConfigureRequest, response *datasource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	client, ok := request.ProviderData.(*flespi.Client)
+
+	if !ok {
+		response.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *flespi.Client, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *gwDevicesDataSource) Schema(ctx context.Context, request datasource.SchemaRequest, response *datasource.SchemaResponse) {
+	response.Schema = schema.Schema{
+		Description: "Lists flespi devices matching all of the given filters.",
+		Attributes: map[string]schema.Attribute{
+			"name_regex": schema.StringAttribute{
+				Optional:    true,
+				Description: "Regular expression the device name must match.",
+			},
+			"account_id": schema.Int64Attribute{
+				Optional:    true,
+				Description: "List devices as this (sub)account and only return the ones it owns.",
+			},
+			"enabled": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Only return enabled (true) or disabled (false) devices.",
+			},
+			"metadata_key": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only return devices having this metadata key.",
+			},
+			"metadata_value": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only return devices whose metadata_key has this value.",
+			},
+			"devices": schema.ListNestedAttribute{
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: deviceDataSourceAttributes(),
+				},
+			},
+		},
+	}
+}
+
+func (d *gwDevicesDataSource) Read(ctx context.Context, request datasource.ReadRequest, response *datasource.ReadResponse) {
+	var config devicesDataSourceModel
+
+	response.Diagnostics.Append(request.Config.Get(ctx, &config)...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	filter, diags := common.NewListFilter(config.NameRegex, config.AccountId, config.Enabled, config.MetadataKey, config.MetadataValue)
+
+	response.Diagnostics.Append(diags...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	devices, err := listDevices(common.ForAccount(d.client, config.AccountId.ValueInt64()))
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error Reading Flespi Devices",
+			"Could not list Flespi devices: "+err.Error(),
+		)
+		return
+	}
+
+	config.Devices = []deviceDataSourceModel{}
+
+	for _, device := range devices {
+		if !filter.MatchName(device.Name) || !filter.MatchAccountId(device.AccountId) ||
+			!filter.MatchEnabled(device.Enabled) || !filter.MatchMetadata(device.metadata()) {
+			continue
+		}
+
+		model, diags := convertFlespiDeviceToDataSourceModel(ctx, &device)
+
+		response.Diagnostics.Append(diags...)
+
+		if response.Diagnostics.HasError() {
+			return
+		}
+
+		config.Devices = append(config.Devices, *model)
+	}
+
+	response.Diagnostics.Append(response.State.Set(ctx, &config)...)
+}
+
+func convertFlespiDeviceToDataSourceModel(ctx context.Context, device *deviceObject) (*deviceDataSourceModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	metadata, metaDiags := types.MapValueFrom(ctx, types.StringType, device.metadata())
+	diags.Append(metaDiags...)
+
+	return &deviceDataSourceModel{
+		Id:             types.Int64Value(device.Id),
+		Name:           types.StringValue(device.Name),
+		Enabled:        types.BoolValue(device.Enabled),
+		DeviceTypeId:   types.Int64Value(device.DeviceTypeId),
+		MessagesTTL:    types.Int64Value(device.MessagesTTL),
+		MessagesRotate: types.Int64Value(device.MessagesRotate),
+		MediaTTL:       types.Int64Value(device.MediaTTL),
+		MediaRotate:    types.Int64Value(device.MediaRotate),
+		Configuration:  jsontypes.NewNormalizedValue(rawJSONString(device.Configuration)),
+		Metadata:       metadata,
+		AccountId:      types.Int64Value(device.AccountId),
+	}, diags
+}
@@ -0,0 +1,150 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	flespi "github.com/mixser/flespi-client"
+)
+
+var (
+	_ datasource.DataSource              = &gwDeviceTypeDataSource{}
+	_ datasource.DataSourceWithConfigure = &gwDeviceTypeDataSource{}
+)
+
+type gwDeviceTypeDataSource struct {
+	client *flespi.Client
+}
+
+type deviceTypeDataSourceModel struct {
+	Protocol      types.String         `tfsdk:"protocol"`
+	Model         types.String         `tfsdk:"model"`
+	Id            types.Int64          `tfsdk:"id"`
+	ProtocolId    types.Int64          `tfsdk:"protocol_id"`
+	Name          types.String         `tfsdk:"name"`
+	Title         types.String         `tfsdk:"title"`
+	Configuration jsontypes.Normalized `tfsdk:"configuration_schema"`
+}
+
+func NewDeviceTypeDataSource() datasource.DataSource {
+	return &gwDeviceTypeDataSource{}
+}
+
+func (d *gwDeviceTypeDataSource) Metadata(ctx context.Context, request datasource.MetadataRequest, response *datasource.MetadataResponse) {
+	response.TypeName = request.ProviderTypeName + "_device_type"
+}
+
+func (d *gwDeviceTypeDataSource) Configure(ctx context.Context, request datasource.ConfigureRequest, response *datasource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	client, ok := request.ProviderData.(*flespi.Client)
+
+	if !ok {
+		response.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *flespi.Client, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *gwDeviceTypeDataSource) Schema(ctx context.Context, request datasource.SchemaRequest, response *datasource.SchemaResponse) {
+	response.Schema = schema.Schema{
+		Description: "Resolves a flespi device type by protocol and model name, e.g. \"teltonika\" / \"FMB920\".",
+		Attributes: map[string]schema.Attribute{
+			"protocol": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the channel protocol the device type belongs to (case-insensitive).",
+			},
+			"model": schema.StringAttribute{
+				Required:    true,
+				Description: "Device type name (e.g. \"teltonika-fmb920\") or title (e.g. \"FMB920\"), case-insensitive.",
+			},
+			"id": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Device type ID, to be used as device_type_id of flespi_device.",
+			},
+			"protocol_id": schema.Int64Attribute{
+				Computed: true,
+			},
+			"name": schema.StringAttribute{
+				Computed: true,
+			},
+			"title": schema.StringAttribute{
+				Computed: true,
+			},
+			"configuration_schema": schema.StringAttribute{
+				Computed:    true,
+				CustomType:  jsontypes.NormalizedType{},
+				Description: "JSON schema of the device configuration for this device type.",
+			},
+		},
+	}
+}
+
+func (d *gwDeviceTypeDataSource) Read(ctx context.Context, request datasource.ReadRequest, response *datasource.ReadResponse) {
+	var config deviceTypeDataSourceModel
+
+	response.Diagnostics.Append(request.Config.Get(ctx, &config)...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	protocols, err := listChannelProtocols(d.client)
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error Reading Flespi Channel Protocols",
+			"Could not list Flespi channel protocols: "+err.Error(),
+		)
+		return
+	}
+
+	protocol, err := findChannelProtocol(protocols, config.Protocol.ValueString())
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error Reading Flespi Device Type",
+			"Could not look up Flespi channel protocol: "+err.Error(),
+		)
+		return
+	}
+
+	deviceTypes, err := listDeviceTypes(d.client, protocol.Id)
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error Reading Flespi Device Type",
+			"Could not list Flespi device types: "+err.Error(),
+		)
+		return
+	}
+
+	found, err := findDeviceType(deviceTypes, config.Model.ValueString())
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error Reading Flespi Device Type",
+			fmt.Sprintf("Could not look up device type of protocol %q: %s", protocol.Name, err),
+		)
+		return
+	}
+
+	config.Id = types.Int64Value(found.Id)
+	config.ProtocolId = types.Int64Value(protocol.Id)
+	config.Name = types.StringValue(found.Name)
+	config.Title = types.StringValue(found.Title)
+	config.Configuration = jsontypes.NewNormalizedValue(rawJSONString(found.Schema))
+
+	response.Diagnostics.Append(response.State.Set(ctx, &config)...)
+}
@@ -4,23 +4,34 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"terraform-provider-flespi/internal/provider/resources/common"
 
 	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	flespi "github.com/mixser/flespi-client"
 	flespi_geofence "github.com/mixser/flespi-client/resources/gateway/geofence"
 )
 
 var (
-	_ resource.Resource              = &gwDeviceResource{}
-	_ resource.ResourceWithConfigure = &gwDeviceResource{}
+	_ resource.Resource                     = &gwGeofenceResource{}
+	_ resource.ResourceWithConfigure        = &gwGeofenceResource{}
+	_ resource.ResourceWithImportState      = &gwGeofenceResource{}
+	_ resource.ResourceWithConfigValidators = &gwGeofenceResource{}
+	_ resource.ResourceWithValidateConfig   = &gwGeofenceResource{}
 )
 
 type gwGeofenceResource struct {
-	client *flespi_geofence.GeofenceClient
+	client   *flespi_geofence.GeofenceClient
+	provider *flespi.Client
 }
 
 type geofenceResourceModel struct {
@@ -30,8 +41,31 @@ type geofenceResourceModel struct {
 	Enabled  types.Bool  `tfsdk:"enabled"`
 	Priority types.Int64 `tfsdk:"priority"`
 
-	Geometry  jsontypes.Normalized `tfsdk:"geometry"`
-	AccountId types.Int64          `tfsdk:"account_id"`
+	Geometry jsontypes.Normalized   `tfsdk:"geometry"`
+	Circle   *geofenceCircleModel   `tfsdk:"circle"`
+	Polygon  *geofencePolygonModel  `tfsdk:"polygon"`
+	Corridor *geofenceCorridorModel `tfsdk:"corridor"`
+
+	AccountId types.Int64 `tfsdk:"account_id"`
+}
+
+type geofencePointModel struct {
+	Lat types.Float64 `tfsdk:"lat"`
+	Lon types.Float64 `tfsdk:"lon"`
+}
+
+type geofenceCircleModel struct {
+	Center geofencePointModel `tfsdk:"center"`
+	Radius types.Float64      `tfsdk:"radius"`
+}
+
+type geofencePolygonModel struct {
+	Points []geofencePointModel `tfsdk:"points"`
+}
+
+type geofenceCorridorModel struct {
+	Path  []geofencePointModel `tfsdk:"path"`
+	Width types.Float64        `tfsdk:"width"`
 }
 
 func NewGeofenceResource() resource.Resource {
@@ -42,6 +76,25 @@ func (g *gwGeofenceResource) Metadata(ctx context.Context, req resource.Metadata
 	resp.TypeName = req.ProviderTypeName + "_geofence"
 }
 
+func geofencePointAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"lat": schema.Float64Attribute{
+			Required:    true,
+			Description: "Latitude in degrees",
+			Validators: []validator.Float64{
+				float64validator.Between(-90, 90),
+			},
+		},
+		"lon": schema.Float64Attribute{
+			Required:    true,
+			Description: "Longitude in degrees",
+			Validators: []validator.Float64{
+				float64validator.Between(-180, 180),
+			},
+		},
+	}
+}
+
 func (g *gwGeofenceResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
@@ -61,8 +114,65 @@ func (g *gwGeofenceResource) Schema(ctx context.Context, req resource.SchemaRequ
 			},
 			"geometry": schema.StringAttribute{
 				CustomType:  jsontypes.NormalizedType{},
-				Required:    true,
-				Description: "GeoJSON geometry (circle, polygon, or corridor)",
+				Optional:    true,
+				Computed:    true,
+				Description: "Geometry as JSON (circle, polygon, or corridor). Prefer the circle, polygon and corridor attributes; this form is for geometry produced elsewhere, such as by the `provider::flespi::geofence_from_geojson` and `provider::flespi::geofence_from_kml` functions.",
+			},
+			"circle": schema.SingleNestedAttribute{
+				Optional:    true,
+				Description: "Circle geometry",
+				Attributes: map[string]schema.Attribute{
+					"center": schema.SingleNestedAttribute{
+						Required:   true,
+						Attributes: geofencePointAttributes(),
+					},
+					"radius": schema.Float64Attribute{
+						Required:    true,
+						Description: "Radius in kilometers",
+						Validators: []validator.Float64{
+							float64validator.AtLeast(0),
+						},
+					},
+				},
+			},
+			"polygon": schema.SingleNestedAttribute{
+				Optional:    true,
+				Description: "Polygon geometry",
+				Attributes: map[string]schema.Attribute{
+					"points": schema.ListNestedAttribute{
+						Required:    true,
+						Description: "Polygon vertices",
+						NestedObject: schema.NestedAttributeObject{
+							Attributes: geofencePointAttributes(),
+						},
+						Validators: []validator.List{
+							listvalidator.SizeAtLeast(3),
+						},
+					},
+				},
+			},
+			"corridor": schema.SingleNestedAttribute{
+				Optional:    true,
+				Description: "Corridor geometry: a path with a width",
+				Attributes: map[string]schema.Attribute{
+					"path": schema.ListNestedAttribute{
+						Required:    true,
+						Description: "Points along the middle of the corridor",
+						NestedObject: schema.NestedAttributeObject{
+							Attributes: geofencePointAttributes(),
+						},
+						Validators: []validator.List{
+							listvalidator.SizeAtLeast(2),
+						},
+					},
+					"width": schema.Float64Attribute{
+						Required:    true,
+						Description: "Corridor width in kilometers",
+						Validators: []validator.Float64{
+							float64validator.AtLeast(0),
+						},
+					},
+				},
 			},
 			"account_id": schema.Int64Attribute{
 				Optional:    true,
@@ -73,6 +183,36 @@ func (g *gwGeofenceResource) Schema(ctx context.Context, req resource.SchemaRequ
 	}
 }
 
+func (g *gwGeofenceResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.ExactlyOneOf(
+			path.MatchRoot("geometry"),
+			path.MatchRoot("circle"),
+			path.MatchRoot("polygon"),
+			path.MatchRoot("corridor"),
+		),
+	}
+}
+
+// ValidateConfig checks the geometry as a whole during plan, which covers the
+// JSON form and what the attribute validators can't see, like a zero radius.
+// Geometry that still depends on unknown values is checked on apply.
+func (g *gwGeofenceResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data geofenceResourceModel
+
+	if req.Config.Get(ctx, &data).HasError() || !data.geometryKnown() {
+		return
+	}
+
+	if _, err := data.geometry(); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			data.geometryPath(),
+			"Invalid Geofence Geometry",
+			err.Error(),
+		)
+	}
+}
+
 func (g *gwGeofenceResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
@@ -88,6 +228,12 @@ func (g *gwGeofenceResource) Configure(ctx context.Context, req resource.Configu
 	}
 
 	g.client = client.Geofences
+	g.provider = client
+}
+
+// clientFor returns a geofence client acting as the (sub)account that owns the geofence.
+func (g *gwGeofenceResource) clientFor(accountId types.Int64) *flespi_geofence.GeofenceClient {
+	return common.ForAccount(g.provider, accountId.ValueInt64()).Geofences
 }
 
 func (g *gwGeofenceResource) Create(ctx context.Context, request resource.CreateRequest, response *resource.CreateResponse) {
@@ -126,7 +272,17 @@ func (g *gwGeofenceResource) Create(ctx context.Context, request resource.Create
 		return
 	}
 
-	result, diags := g.convertFlespiGeofenceToResourceModel(*geofenceInstance)
+	createdGeofence, err := getGeofence(common.ForAccount(g.provider, data.AccountId.ValueInt64()), geofenceInstance.Id)
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Failed to read geofence after creation",
+			fmt.Sprintf("Error reading geofence: %s", err),
+		)
+		return
+	}
+
+	result, diags := g.convertFlespiGeofenceToResourceModel(*createdGeofence, *data)
 
 	response.Diagnostics.Append(diags)
 	response.Diagnostics.Append(response.State.Set(ctx, &result)...)
@@ -141,7 +297,13 @@ func (g *gwGeofenceResource) Read(ctx context.Context, request resource.ReadRequ
 		return
 	}
 
-	geofenceInstance, err := g.client.GetById(data.ID.ValueInt64())
+	geofenceInstance, err := getGeofence(common.ForAccount(g.provider, data.AccountId.ValueInt64()), data.ID.ValueInt64())
+
+	if common.IsNotFound(err) {
+		tflog.Warn(ctx, "Flespi geofence not found, removing from state", map[string]interface{}{"id": data.ID.ValueInt64()})
+		response.State.RemoveResource(ctx)
+		return
+	}
 
 	if err != nil {
 		response.Diagnostics.AddError(
@@ -151,7 +313,7 @@ func (g *gwGeofenceResource) Read(ctx context.Context, request resource.ReadRequ
 		return
 	}
 
-	result, diags := g.convertFlespiGeofenceToResourceModel(*geofenceInstance)
+	result, diags := g.convertFlespiGeofenceToResourceModel(*geofenceInstance, *data)
 
 	response.Diagnostics.Append(diags)
 	response.Diagnostics.Append(response.State.Set(ctx, &result)...)
@@ -177,7 +339,7 @@ func (g *gwGeofenceResource) Update(ctx context.Context, request resource.Update
 		return
 	}
 
-	_, err := g.client.Update(instance)
+	_, err := g.clientFor(state.AccountId).Update(instance)
 
 	if err != nil {
 		response.Diagnostics.AddError(
@@ -187,7 +349,7 @@ func (g *gwGeofenceResource) Update(ctx context.Context, request resource.Update
 		return
 	}
 
-	updatedGeofence, err := g.client.GetById(state.ID.ValueInt64())
+	updatedGeofence, err := getGeofence(common.ForAccount(g.provider, state.AccountId.ValueInt64()), state.ID.ValueInt64())
 
 	if err != nil {
 		response.Diagnostics.AddError(
@@ -197,7 +359,7 @@ func (g *gwGeofenceResource) Update(ctx context.Context, request resource.Update
 		return
 	}
 
-	result, diags := g.convertFlespiGeofenceToResourceModel(*updatedGeofence)
+	result, diags := g.convertFlespiGeofenceToResourceModel(*updatedGeofence, plan)
 
 	response.Diagnostics.Append(diags)
 	response.Diagnostics.Append(response.State.Set(ctx, &result)...)
@@ -212,9 +374,9 @@ func (g *gwGeofenceResource) Delete(ctx context.Context, request resource.Delete
 		return
 	}
 
-	err := g.client.DeleteById(data.ID.ValueInt64())
+	err := g.clientFor(data.AccountId).DeleteById(data.ID.ValueInt64())
 
-	if err != nil {
+	if err != nil && !common.IsNotFound(err) {
 		response.Diagnostics.AddError(
 			"Failed to delete geofence",
 			fmt.Sprintf("Error deleting geofence: %s", err),
@@ -223,32 +385,85 @@ func (g *gwGeofenceResource) Delete(ctx context.Context, request resource.Delete
 	}
 }
 
-func (g *gwGeofenceResource) convertResourceModelToFlespiGeofence(data geofenceResourceModel) (flespi_geofence.Geofence, diag.Diagnostic) {
-	var geometry flespi_geofence.GeofenceGeometry
+func (g *gwGeofenceResource) ImportState(ctx context.Context, request resource.ImportStateRequest, response *resource.ImportStateResponse) {
+	common.ImportStateByAccountScopedId(ctx, request, response)
+}
 
-	// Parse geometry from JSON
-	if !data.Geometry.IsNull() && !data.Geometry.IsUnknown() {
-		geometryJSON := data.Geometry.ValueString()
-		if geometryJSON != "" {
-			var rawGeometry map[string]interface{}
-			if err := json.Unmarshal([]byte(geometryJSON), &rawGeometry); err != nil {
-				return flespi_geofence.Geofence{}, diag.NewErrorDiagnostic(
-					"Invalid geometry JSON",
-					fmt.Sprintf("Failed to parse geometry: %s", err),
-				)
-			}
+// geometryPath is the attribute the geometry is configured with.
+func (data geofenceResourceModel) geometryPath() path.Path {
+	switch {
+	case data.Circle != nil:
+		return path.Root("circle")
+	case data.Polygon != nil:
+		return path.Root("polygon")
+	case data.Corridor != nil:
+		return path.Root("corridor")
+	default:
+		return path.Root("geometry")
+	}
+}
 
-			// Re-marshal to use with UnmarshalGeometry
-			geometryBytes, _ := json.Marshal(rawGeometry)
-			var err error
-			geometry, err = flespi_geofence.UnmarshalGeometry(geometryBytes)
-			if err != nil {
-				return flespi_geofence.Geofence{}, diag.NewErrorDiagnostic(
-					"Invalid geometry format",
-					fmt.Sprintf("Failed to unmarshal geometry: %s", err),
-				)
+// geometryKnown reports whether every value making up the geometry is known.
+func (data geofenceResourceModel) geometryKnown() bool {
+	known := func(points ...geofencePointModel) bool {
+		for _, point := range points {
+			if point.Lat.IsUnknown() || point.Lon.IsUnknown() {
+				return false
 			}
 		}
+
+		return true
+	}
+
+	switch {
+	case data.Circle != nil:
+		return known(data.Circle.Center) && !data.Circle.Radius.IsUnknown()
+	case data.Polygon != nil:
+		return known(data.Polygon.Points...)
+	case data.Corridor != nil:
+		return known(data.Corridor.Path...) && !data.Corridor.Width.IsUnknown()
+	default:
+		return !data.Geometry.IsNull() && !data.Geometry.IsUnknown()
+	}
+}
+
+// geometry builds the flespi geometry from whichever form is configured.
+func (data geofenceResourceModel) geometry() (flespi_geofence.GeofenceGeometry, error) {
+	points := func(models []geofencePointModel) []flespi_geofence.Point {
+		result := make([]flespi_geofence.Point, 0, len(models))
+
+		for _, model := range models {
+			result = append(result, flespi_geofence.Point{Latitude: model.Lat.ValueFloat64(), Longitude: model.Lon.ValueFloat64()})
+		}
+
+		return result
+	}
+
+	var geometry flespi_geofence.GeofenceGeometry
+
+	switch {
+	case data.Circle != nil:
+		geometry = flespi_geofence.NewCircle(points([]geofencePointModel{data.Circle.Center})[0], data.Circle.Radius.ValueFloat64())
+	case data.Polygon != nil:
+		geometry = flespi_geofence.NewPolygon(points(data.Polygon.Points))
+	case data.Corridor != nil:
+		geometry = flespi_geofence.NewCorridor(points(data.Corridor.Path), data.Corridor.Width.ValueFloat64())
+	default:
+		return parseGeometry(json.RawMessage(data.Geometry.ValueString()))
+	}
+
+	return geometry, checkGeometry(geometry)
+}
+
+func (g *gwGeofenceResource) convertResourceModelToFlespiGeofence(data geofenceResourceModel) (flespi_geofence.Geofence, diag.Diagnostic) {
+	geometry, err := data.geometry()
+
+	if err != nil {
+		return flespi_geofence.Geofence{}, diag.NewAttributeErrorDiagnostic(
+			data.geometryPath(),
+			"Invalid Geofence Geometry",
+			err.Error(),
+		)
 	}
 
 	return flespi_geofence.Geofence{
@@ -261,29 +476,66 @@ func (g *gwGeofenceResource) convertResourceModelToFlespiGeofence(data geofenceR
 	}, nil
 }
 
-func (g *gwGeofenceResource) convertFlespiGeofenceToResourceModel(data flespi_geofence.Geofence) (*geofenceResourceModel, diag.Diagnostic) {
+// convertFlespiGeofenceToResourceModel builds the state of data. configured is
+// the plan or prior state and decides the form the geometry is kept in: the
+// typed attribute matching the geometry type when one of them is in use,
+// otherwise the JSON form only.
+func (g *gwGeofenceResource) convertFlespiGeofenceToResourceModel(data geofenceObject, configured geofenceResourceModel) (*geofenceResourceModel, diag.Diagnostic) {
 	var result geofenceResourceModel
 
 	result.ID = types.Int64Value(data.Id)
 	result.Name = types.StringValue(data.Name)
 	result.Enabled = types.BoolValue(data.Enabled)
 	result.Priority = types.Int64Value(data.Priority)
+	result.AccountId = types.Int64Value(data.AccountId)
 
-	// Convert geometry to JSON
-	if data.Geometry != nil {
-		geometryBytes, err := json.Marshal(data.Geometry)
-		if err != nil {
-			return nil, diag.NewErrorDiagnostic(
-				"Failed to marshal geometry",
-				fmt.Sprintf("Error converting geometry to JSON: %s", err),
-			)
-		}
-		result.Geometry = jsontypes.NewNormalizedValue(string(geometryBytes))
-	} else {
+	if len(data.Geometry) == 0 || string(data.Geometry) == "null" {
 		result.Geometry = jsontypes.NewNormalizedNull()
+		return &result, nil
 	}
 
-	result.AccountId = types.Int64Value(data.AccountId)
+	// After import the state holds neither form of the geometry yet, so it is
+	// read into the attribute matching geometry.type, the form the schema
+	// prefers, and the first plan of a matching configuration is empty.
+	typed := configured.Circle != nil || configured.Polygon != nil || configured.Corridor != nil || configured.Geometry.IsNull()
+
+	if !typed {
+		result.Geometry = common.PreferConfiguredJSON(configured.Geometry, string(data.Geometry))
+		return &result, nil
+	}
+
+	result.Geometry = jsontypes.NewNormalizedValue(string(data.Geometry))
+
+	geometry, err := parseGeometry(data.Geometry)
+
+	if err != nil {
+		return &result, diag.NewWarningDiagnostic(
+			"Unexpected Geofence Geometry",
+			fmt.Sprintf("Geofence %d has geometry the provider can't represent, it is only kept as JSON: %s", data.Id, err),
+		)
+	}
+
+	points := func(points []flespi_geofence.Point) []geofencePointModel {
+		result := make([]geofencePointModel, 0, len(points))
+
+		for _, point := range points {
+			result = append(result, geofencePointModel{Lat: types.Float64Value(point.Latitude), Lon: types.Float64Value(point.Longitude)})
+		}
+
+		return result
+	}
+
+	switch typedGeometry := geometry.(type) {
+	case *flespi_geofence.Circle:
+		result.Circle = &geofenceCircleModel{
+			Center: points([]flespi_geofence.Point{typedGeometry.Center})[0],
+			Radius: types.Float64Value(typedGeometry.Radius),
+		}
+	case *flespi_geofence.Polygon:
+		result.Polygon = &geofencePolygonModel{Points: points(typedGeometry.Path)}
+	case *flespi_geofence.Corridor:
+		result.Corridor = &geofenceCorridorModel{Path: points(typedGeometry.Path), Width: types.Float64Value(typedGeometry.Width)}
+	}
 
 	return &result, nil
 }
@@ -0,0 +1,128 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"terraform-provider-flespi/internal/provider/resources/common"
+
+	flespi "github.com/mixser/flespi-client"
+)
+
+// geofenceObject mirrors flespi_geofence.Geofence but keeps the geometry as
+// raw JSON. The client library decodes every geometry as a circle first and
+// turns polygons and corridors into empty circles, so geofences are read
+// through the raw gw/geofences endpoints. Writes of single geofences still go
+// through the library; geofence sets write raw too, as the library has no
+// metadata and no batch requests.
+type geofenceObject struct {
+	Id        int64              `json:"id,omitempty"`
+	Name      string             `json:"name"`
+	Enabled   bool               `json:"enabled"`
+	Priority  int64              `json:"priority"`
+	Geometry  json.RawMessage    `json:"geometry"`
+	Metadata  *map[string]string `json:"metadata,omitempty"`
+	AccountId int64              `json:"cid,omitempty"`
+}
+
+// metadata returns the geofence metadata as read from the API.
+func (g geofenceObject) metadata() map[string]string {
+	if g.Metadata == nil {
+		return nil
+	}
+
+	return *g.Metadata
+}
+
+type geofencesResponse struct {
+	Geofences []geofenceObject `json:"result"`
+}
+
+const geofenceFields = "id,name,enabled,priority,geometry,cid"
+
+// geofenceBatchSize is the number of geofences created or deleted per request.
+const geofenceBatchSize = 100
+
+func getGeofence(client *flespi.Client, geofenceId int64) (*geofenceObject, error) {
+	response := geofencesResponse{}
+
+	endpoint := fmt.Sprintf("gw/geofences/%d?fields=%s", geofenceId, geofenceFields)
+
+	if err := client.RequestAPI("GET", endpoint, nil, &response); err != nil {
+		return nil, err
+	}
+
+	if len(response.Geofences) == 0 {
+		return nil, &flespi.APIError{StatusCode: 404, Method: "GET", Endpoint: endpoint, Message: "geofence not found"}
+	}
+
+	return &response.Geofences[0], nil
+}
+
+func listGeofences(client *flespi.Client) ([]geofenceObject, error) {
+	response := geofencesResponse{}
+
+	if err := client.RequestAPI("GET", "gw/geofences/all?fields="+geofenceFields, nil, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Geofences, nil
+}
+
+// listGeofencesWithMetadata returns every geofence with its metadata, which
+// geofence sets use to recognize their members.
+func listGeofencesWithMetadata(client *flespi.Client) ([]geofenceObject, error) {
+	response := geofencesResponse{}
+
+	if err := client.RequestAPI("GET", "gw/geofences/all?fields="+geofenceFields+",metadata", nil, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Geofences, nil
+}
+
+// createGeofences creates geofences under accountId in batches of
+// geofenceBatchSize and returns them in the order they were passed.
+func createGeofences(client *flespi.Client, accountId int64, geofences []geofenceObject) ([]geofenceObject, error) {
+	headers := common.AccountHeaders(accountId)
+	created := make([]geofenceObject, 0, len(geofences))
+
+	for start := 0; start < len(geofences); start += geofenceBatchSize {
+		batch := geofences[start:min(start+geofenceBatchSize, len(geofences))]
+		response := geofencesResponse{}
+
+		if err := client.RequestAPIWithHeaders("POST", "gw/geofences?fields="+geofenceFields+",metadata", headers, batch, &response); err != nil {
+			return created, err
+		}
+
+		if len(response.Geofences) != len(batch) {
+			return created, fmt.Errorf("flespi returned %d geofences for %d created", len(response.Geofences), len(batch))
+		}
+
+		created = append(created, response.Geofences...)
+	}
+
+	return created, nil
+}
+
+func updateGeofence(client *flespi.Client, geofence geofenceObject) error {
+	geofenceId := geofence.Id
+	headers := common.AccountHeaders(geofence.AccountId)
+
+	geofence.Id = 0
+	geofence.AccountId = 0
+
+	return client.RequestAPIWithHeaders("PUT", fmt.Sprintf("gw/geofences/%d", geofenceId), headers, geofence, nil)
+}
+
+// deleteGeofences deletes geofences by ID in batches of geofenceBatchSize.
+func deleteGeofences(client *flespi.Client, geofenceIds []int64) error {
+	for start := 0; start < len(geofenceIds); start += geofenceBatchSize {
+		batch := geofenceIds[start:min(start+geofenceBatchSize, len(geofenceIds))]
+
+		if err := client.RequestAPI("DELETE", "gw/geofences/"+idSelector(batch), nil, nil); err != nil && !common.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
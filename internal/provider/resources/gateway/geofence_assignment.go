@@ -0,0 +1,37 @@
+package gateway
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+)
+
+// The geofence client of the library has no assignment methods, so geofences
+// are bound to devices through the raw gw/geofences/{id}/devices endpoints.
+var geofenceDeviceAssignment = assignmentKind{
+	typeName:        "geofence_device_assignment",
+	description:     "Assigns a flespi geofence to a device, so geofence events fire for the device messages.",
+	parentEndpoint:  "geofences",
+	parentAttribute: "geofence_id",
+	parentTitle:     "geofence",
+	childEndpoint:   "devices",
+	childAttribute:  "device_id",
+	childTitle:      "device",
+}
+
+func NewGeofenceDeviceAssignmentResource() resource.Resource {
+	return &gwAssignmentResource{
+		kind: geofenceDeviceAssignment,
+	}
+}
+
+// NewGeofenceDeviceAssignmentsResource assigns a geofence to a set of devices
+// in one resource.
+func NewGeofenceDeviceAssignmentsResource() resource.Resource {
+	kind := geofenceDeviceAssignment
+	kind.typeName = "geofence_device_assignments"
+	kind.description = "Assigns a flespi geofence to a set of devices, so geofence events fire for their messages."
+
+	return &gwAssignmentSetResource{
+		kind:              kind,
+		childrenAttribute: "device_ids",
+	}
+}
@@ -0,0 +1,479 @@
+package gateway
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	flespi_geofence "github.com/mixser/flespi-client/resources/gateway/geofence"
+)
+
+// maxGeofencePoints is the number of points polygons and corridor paths are
+// simplified down to when converted from GeoJSON or KML.
+const maxGeofencePoints = 500
+
+// geoJSONObject is a GeoJSON Feature, FeatureCollection or bare geometry.
+type geoJSONObject struct {
+	Type        string                 `json:"type"`
+	Id          interface{}            `json:"id"`
+	Coordinates json.RawMessage        `json:"coordinates"`
+	Geometry    *geoJSONObject         `json:"geometry"`
+	Features    []geoJSONObject        `json:"features"`
+	Properties  map[string]interface{} `json:"properties"`
+}
+
+// geometryFromGeoJSON converts a GeoJSON Feature, or a bare geometry, into
+// flespi geofence geometry. Polygons become polygons, LineStrings become
+// corridors and Points become circles; the corridor width and circle radius,
+// in kilometers, are taken from the "width" and "radius" feature properties.
+func geometryFromGeoJSON(document string) (flespi_geofence.GeofenceGeometry, error) {
+	var object geoJSONObject
+
+	if err := json.Unmarshal([]byte(document), &object); err != nil {
+		return nil, fmt.Errorf("invalid GeoJSON: %w", err)
+	}
+
+	properties := object.Properties
+
+	switch object.Type {
+	case "FeatureCollection":
+		if len(object.Features) != 1 {
+			return nil, fmt.Errorf("expected a single Feature, got a FeatureCollection with %d features; pick one, e.g. jsonencode(jsondecode(file(\"areas.geojson\")).features[0])", len(object.Features))
+		}
+
+		properties = object.Features[0].Properties
+		object = object.Features[0]
+
+		fallthrough
+	case "Feature":
+		if object.Geometry == nil {
+			return nil, fmt.Errorf("feature has no geometry")
+		}
+
+		object = *object.Geometry
+	}
+
+	return geoJSONGeometry(object, properties)
+}
+
+// geoJSONGeometry converts a GeoJSON geometry, with the properties of the
+// Feature holding it, into flespi geofence geometry.
+func geoJSONGeometry(object geoJSONObject, properties map[string]interface{}) (flespi_geofence.GeofenceGeometry, error) {
+	number := func(name string) (float64, error) {
+		value, ok := properties[name].(float64)
+
+		if !ok {
+			return 0, fmt.Errorf("%s geometry needs a numeric %q feature property", object.Type, name)
+		}
+
+		return value, nil
+	}
+
+	switch object.Type {
+	case "Point":
+		var coordinates []float64
+
+		if err := json.Unmarshal(object.Coordinates, &coordinates); err != nil || len(coordinates) < 2 {
+			return nil, fmt.Errorf("invalid Point coordinates")
+		}
+
+		radius, err := number("radius")
+
+		if err != nil {
+			return nil, err
+		}
+
+		return geometryChecked(flespi_geofence.NewCircle(flespi_geofence.Point{Latitude: coordinates[1], Longitude: coordinates[0]}, radius))
+	case "LineString":
+		var coordinates [][]float64
+
+		if err := json.Unmarshal(object.Coordinates, &coordinates); err != nil {
+			return nil, fmt.Errorf("invalid LineString coordinates: %w", err)
+		}
+
+		width, err := number("width")
+
+		if err != nil {
+			return nil, err
+		}
+
+		path, err := geoJSONPoints(coordinates)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return geometryChecked(flespi_geofence.NewCorridor(simplifyPath(path, maxGeofencePoints), width))
+	case "Polygon":
+		var rings [][][]float64
+
+		if err := json.Unmarshal(object.Coordinates, &rings); err != nil {
+			return nil, fmt.Errorf("invalid Polygon coordinates: %w", err)
+		}
+
+		return polygonFromRings(rings)
+	case "MultiPolygon":
+		var polygons [][][][]float64
+
+		if err := json.Unmarshal(object.Coordinates, &polygons); err != nil {
+			return nil, fmt.Errorf("invalid MultiPolygon coordinates: %w", err)
+		}
+
+		if len(polygons) != 1 {
+			return nil, fmt.Errorf("MultiPolygon with %d polygons is not supported, a flespi geofence holds a single polygon", len(polygons))
+		}
+
+		return polygonFromRings(polygons[0])
+	default:
+		return nil, fmt.Errorf("unsupported GeoJSON geometry type %q, expected Polygon, LineString (with a width property) or Point (with a radius property)", object.Type)
+	}
+}
+
+func polygonFromRings(rings [][][]float64) (flespi_geofence.GeofenceGeometry, error) {
+	if len(rings) == 0 {
+		return nil, fmt.Errorf("polygon has no rings")
+	}
+
+	if len(rings) > 1 {
+		return nil, fmt.Errorf("polygon with %d holes is not supported, flespi polygons have no holes", len(rings)-1)
+	}
+
+	path, err := geoJSONPoints(rings[0])
+
+	if err != nil {
+		return nil, err
+	}
+
+	return geometryChecked(flespi_geofence.NewPolygon(simplifyPath(openRing(path), maxGeofencePoints)))
+}
+
+func geoJSONPoints(coordinates [][]float64) ([]flespi_geofence.Point, error) {
+	points := make([]flespi_geofence.Point, 0, len(coordinates))
+
+	for i, position := range coordinates {
+		if len(position) < 2 {
+			return nil, fmt.Errorf("position %d has %d coordinates, expected [lon, lat]", i, len(position))
+		}
+
+		points = append(points, flespi_geofence.Point{Latitude: position[1], Longitude: position[0]})
+	}
+
+	return points, nil
+}
+
+// geometryToGeoJSON is the inverse of geoJSONGeometry: it returns the GeoJSON
+// geometry for geometry and the feature properties holding its radius or width.
+func geometryToGeoJSON(geometry flespi_geofence.GeofenceGeometry) (map[string]interface{}, map[string]interface{}) {
+	positions := func(points []flespi_geofence.Point) [][]float64 {
+		result := make([][]float64, 0, len(points)+1)
+
+		for _, point := range points {
+			result = append(result, []float64{point.Longitude, point.Latitude})
+		}
+
+		return result
+	}
+
+	switch typed := geometry.(type) {
+	case *flespi_geofence.Circle:
+		return map[string]interface{}{
+			"type":        "Point",
+			"coordinates": []float64{typed.Center.Longitude, typed.Center.Latitude},
+		}, map[string]interface{}{"radius": typed.Radius}
+	case *flespi_geofence.Corridor:
+		return map[string]interface{}{
+			"type":        "LineString",
+			"coordinates": positions(typed.Path),
+		}, map[string]interface{}{"width": typed.Width}
+	case *flespi_geofence.Polygon:
+		ring := positions(typed.Path)
+
+		if len(ring) > 0 {
+			ring = append(ring, ring[0])
+		}
+
+		return map[string]interface{}{
+			"type":        "Polygon",
+			"coordinates": [][][]float64{ring},
+		}, map[string]interface{}{}
+	default:
+		return nil, map[string]interface{}{}
+	}
+}
+
+// kmlPlacemark is any KML element; only Placemarks carry geometry, the rest
+// are walked through Children to find them.
+type kmlPlacemark struct {
+	XMLName xml.Name
+	Name    string `xml:"name"`
+
+	Point *struct {
+		Coordinates string `xml:"coordinates"`
+	} `xml:"Point"`
+	LineString *struct {
+		Coordinates string `xml:"coordinates"`
+	} `xml:"LineString"`
+	Polygon *struct {
+		Outer string `xml:"outerBoundaryIs>LinearRing>coordinates"`
+		Inner []struct {
+			Coordinates string `xml:"LinearRing>coordinates"`
+		} `xml:"innerBoundaryIs"`
+	} `xml:"Polygon"`
+
+	Data []struct {
+		Name  string `xml:"name,attr"`
+		Value string `xml:"value"`
+	} `xml:"ExtendedData>Data"`
+
+	Children []kmlPlacemark `xml:",any"`
+}
+
+// placemarks flattens the element tree into the Placemarks it contains.
+func (p kmlPlacemark) placemarks() []kmlPlacemark {
+	if p.XMLName.Local == "Placemark" {
+		return []kmlPlacemark{p}
+	}
+
+	var result []kmlPlacemark
+
+	for _, child := range p.Children {
+		result = append(result, child.placemarks()...)
+	}
+
+	return result
+}
+
+// placemarkError reports a Placemark name that does not pick exactly one
+// Placemark, as opposed to a problem with the KML document itself.
+type placemarkError struct {
+	message string
+}
+
+func (e placemarkError) Error() string {
+	return e.message
+}
+
+// geometryFromKML converts the Placemark called name, or the only Placemark
+// when name is empty, into flespi geofence geometry. Like GeoJSON, Points and
+// LineStrings need a "radius" or "width" ExtendedData value in kilometers.
+func geometryFromKML(document, name string) (flespi_geofence.GeofenceGeometry, error) {
+	var root kmlPlacemark
+
+	if err := xml.Unmarshal([]byte(document), &root); err != nil {
+		return nil, fmt.Errorf("invalid KML: %w", err)
+	}
+
+	candidates := root.placemarks()
+	names := make([]string, 0, len(candidates))
+	var matches []kmlPlacemark
+
+	for _, candidate := range candidates {
+		names = append(names, strconv.Quote(candidate.Name))
+
+		if name == "" || candidate.Name == name {
+			matches = append(matches, candidate)
+		}
+	}
+
+	switch {
+	case len(candidates) == 0:
+		return nil, fmt.Errorf("KML has no Placemark")
+	case len(matches) == 0:
+		return nil, placemarkError{fmt.Sprintf("KML has no Placemark named %q, found: %s", name, strings.Join(names, ", "))}
+	case len(matches) > 1 && name == "":
+		return nil, placemarkError{fmt.Sprintf("KML has %d Placemarks, pick one by name: %s", len(matches), strings.Join(names, ", "))}
+	case len(matches) > 1:
+		return nil, placemarkError{fmt.Sprintf("KML has %d Placemarks named %q", len(matches), name)}
+	}
+
+	placemark := matches[0]
+
+	number := func(kind, key string) (float64, error) {
+		for _, data := range placemark.Data {
+			if data.Name != key {
+				continue
+			}
+
+			value, err := strconv.ParseFloat(strings.TrimSpace(data.Value), 64)
+
+			if err != nil {
+				return 0, fmt.Errorf("%s geometry needs a numeric %q ExtendedData value, got %q", kind, key, data.Value)
+			}
+
+			return value, nil
+		}
+
+		return 0, fmt.Errorf("%s geometry needs a numeric %q ExtendedData value", kind, key)
+	}
+
+	switch {
+	case placemark.Polygon != nil:
+		if len(placemark.Polygon.Inner) > 0 {
+			return nil, fmt.Errorf("polygon with %d holes is not supported, flespi polygons have no holes", len(placemark.Polygon.Inner))
+		}
+
+		path, err := kmlPoints(placemark.Polygon.Outer)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return geometryChecked(flespi_geofence.NewPolygon(simplifyPath(openRing(path), maxGeofencePoints)))
+	case placemark.LineString != nil:
+		path, err := kmlPoints(placemark.LineString.Coordinates)
+
+		if err != nil {
+			return nil, err
+		}
+
+		width, err := number("LineString", "width")
+
+		if err != nil {
+			return nil, err
+		}
+
+		return geometryChecked(flespi_geofence.NewCorridor(simplifyPath(path, maxGeofencePoints), width))
+	case placemark.Point != nil:
+		points, err := kmlPoints(placemark.Point.Coordinates)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if len(points) != 1 {
+			return nil, fmt.Errorf("point has %d positions", len(points))
+		}
+
+		radius, err := number("Point", "radius")
+
+		if err != nil {
+			return nil, err
+		}
+
+		return geometryChecked(flespi_geofence.NewCircle(points[0], radius))
+	default:
+		return nil, fmt.Errorf("placemark %q has no supported geometry, expected Polygon, LineString (with a width) or Point (with a radius)", placemark.Name)
+	}
+}
+
+// kmlPoints parses KML coordinates: "lon,lat[,alt]" tuples separated by
+// whitespace.
+func kmlPoints(coordinates string) ([]flespi_geofence.Point, error) {
+	var points []flespi_geofence.Point
+
+	for _, tuple := range strings.Fields(coordinates) {
+		parts := strings.Split(tuple, ",")
+
+		if len(parts) < 2 {
+			return nil, fmt.Errorf("invalid KML coordinates %q, expected lon,lat[,alt]", tuple)
+		}
+
+		lon, err := strconv.ParseFloat(parts[0], 64)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid KML longitude %q", parts[0])
+		}
+
+		lat, err := strconv.ParseFloat(parts[1], 64)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid KML latitude %q", parts[1])
+		}
+
+		points = append(points, flespi_geofence.Point{Latitude: lat, Longitude: lon})
+	}
+
+	return points, nil
+}
+
+func geometryChecked(geometry flespi_geofence.GeofenceGeometry) (flespi_geofence.GeofenceGeometry, error) {
+	if err := checkGeometry(geometry); err != nil {
+		return nil, err
+	}
+
+	return geometry, nil
+}
+
+// openRing drops the closing point of a ring, which repeats the first one;
+// flespi closes polygons by itself.
+func openRing(ring []flespi_geofence.Point) []flespi_geofence.Point {
+	if len(ring) > 1 && ring[0] == ring[len(ring)-1] {
+		return ring[:len(ring)-1]
+	}
+
+	return ring
+}
+
+// simplifyPath reduces path to at most limit points with the Douglas-Peucker
+// algorithm, raising the tolerance until the result fits. The first and last
+// points are always kept.
+func simplifyPath(path []flespi_geofence.Point, limit int) []flespi_geofence.Point {
+	if len(path) <= limit || limit < 2 {
+		return path
+	}
+
+	for tolerance := 1e-6; ; tolerance *= 2 {
+		keep := make([]bool, len(path))
+		keep[0], keep[len(path)-1] = true, true
+
+		douglasPeucker(path, 0, len(path)-1, tolerance, keep)
+
+		result := make([]flespi_geofence.Point, 0, limit)
+
+		for i, point := range path {
+			if keep[i] {
+				result = append(result, point)
+			}
+		}
+
+		if len(result) <= limit {
+			return result
+		}
+	}
+}
+
+func douglasPeucker(path []flespi_geofence.Point, first, last int, tolerance float64, keep []bool) {
+	if last-first < 2 {
+		return
+	}
+
+	farthest, distance := first, 0.0
+
+	for i := first + 1; i < last; i++ {
+		if d := segmentDistance(path[i], path[first], path[last]); d > distance {
+			farthest, distance = i, d
+		}
+	}
+
+	if distance <= tolerance {
+		return
+	}
+
+	keep[farthest] = true
+
+	douglasPeucker(path, first, farthest, tolerance, keep)
+	douglasPeucker(path, farthest, last, tolerance, keep)
+}
+
+// segmentDistance is the distance in degrees of latitude from p to the segment
+// a-b, with longitudes scaled to the latitude so the measure stays isotropic.
+func segmentDistance(p, a, b flespi_geofence.Point) float64 {
+	scale := math.Cos(a.Latitude * math.Pi / 180)
+
+	px, py := p.Longitude*scale, p.Latitude
+	ax, ay := a.Longitude*scale, a.Latitude
+	bx, by := b.Longitude*scale, b.Latitude
+
+	dx, dy := bx-ax, by-ay
+
+	if dx == 0 && dy == 0 {
+		return math.Hypot(px-ax, py-ay)
+	}
+
+	t := math.Max(0, math.Min(1, ((px-ax)*dx+(py-ay)*dy)/(dx*dx+dy*dy)))
+
+	return math.Hypot(px-(ax+t*dx), py-(ay+t*dy))
+}
@@ -0,0 +1,297 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-flespi/internal/provider/resources/common"
+
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	flespi "github.com/mixser/flespi-client"
+)
+
+var (
+	_ datasource.DataSource                     = &gwGeofenceDataSource{}
+	_ datasource.DataSourceWithConfigure        = &gwGeofenceDataSource{}
+	_ datasource.DataSourceWithConfigValidators = &gwGeofenceDataSource{}
+	_ datasource.DataSource                     = &gwGeofencesDataSource{}
+	_ datasource.DataSourceWithConfigure        = &gwGeofencesDataSource{}
+)
+
+type gwGeofenceDataSource struct {
+	client *flespi.Client
+}
+
+type gwGeofencesDataSource struct {
+	client *flespi.Client
+}
+
+type geofenceDataSourceModel struct {
+	Id        types.Int64          `tfsdk:"id"`
+	Name      types.String         `tfsdk:"name"`
+	Enabled   types.Bool           `tfsdk:"enabled"`
+	Priority  types.Int64          `tfsdk:"priority"`
+	Geometry  jsontypes.Normalized `tfsdk:"geometry"`
+	AccountId types.Int64          `tfsdk:"account_id"`
+}
+
+type geofencesDataSourceModel struct {
+	NameRegex types.String              `tfsdk:"name_regex"`
+	AccountId types.Int64               `tfsdk:"account_id"`
+	Enabled   types.Bool                `tfsdk:"enabled"`
+	Geofences []geofenceDataSourceModel `tfsdk:"geofences"`
+}
+
+func NewGeofenceDataSource() datasource.DataSource {
+	return &gwGeofenceDataSource{}
+}
+
+func NewGeofencesDataSource() datasource.DataSource {
+	return &gwGeofencesDataSource{}
+}
+
+func geofenceDataSourceAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"id": schema.Int64Attribute{
+			Computed: true,
+		},
+		"name": schema.StringAttribute{
+			Computed: true,
+		},
+		"enabled": schema.BoolAttribute{
+			Computed: true,
+		},
+		"priority": schema.Int64Attribute{
+			Computed: true,
+		},
+		"geometry": schema.StringAttribute{
+			Computed:   true,
+			CustomType: jsontypes.NormalizedType{},
+		},
+		"account_id": schema.Int64Attribute{
+			Computed: true,
+		},
+	}
+}
+
+func (d *gwGeofenceDataSource) Metadata(ctx context.Context, request datasource.MetadataRequest, response *datasource.MetadataResponse) {
+	response.TypeName = request.ProviderTypeName + "_geofence"
+}
+
+func (d *gwGeofenceDataSource) Configure(ctx context.Context, request datasource.ConfigureRequest, response *datasource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	client, ok := request.ProviderData.(*flespi.Client)
+
+	if !ok {
+		response.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *flespi.Client, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *gwGeofenceDataSource) Schema(ctx context.Context, request datasource.SchemaRequest, response *datasource.SchemaResponse) {
+	attributes := geofenceDataSourceAttributes()
+
+	attributes["id"] = schema.Int64Attribute{
+		Optional:    true,
+		Computed:    true,
+		Description: "ID of the geofence to look up.",
+	}
+	attributes["name"] = schema.StringAttribute{
+		Optional:    true,
+		Computed:    true,
+		Description: "Exact name of the geofence to look up.",
+	}
+
+	attributes["account_id"] = schema.Int64Attribute{
+		Optional:    true,
+		Computed:    true,
+		Description: "(Sub)account to look the geofence up in. Defaults to the provider's account_id.",
+	}
+
+	response.Schema = schema.Schema{
+		Description: "Looks up a single flespi geofence by id or exact name.",
+		Attributes:  attributes,
+	}
+}
+
+func (d *gwGeofenceDataSource) ConfigValidators(ctx context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		datasourcevalidator.ExactlyOneOf(
+			path.MatchRoot("id"),
+			path.MatchRoot("name"),
+		),
+	}
+}
+
+func (d *gwGeofenceDataSource) Read(ctx context.Context, request datasource.ReadRequest, response *datasource.ReadResponse) {
+	var config geofenceDataSourceModel
+
+	response.Diagnostics.Append(request.Config.Get(ctx, &config)...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	client := common.ForAccount(d.client, config.AccountId.ValueInt64())
+
+	var geofence *geofenceObject
+	var err error
+
+	if !config.Id.IsNull() {
+		geofence, err = getGeofence(client, config.Id.ValueInt64())
+	} else {
+		var geofences []geofenceObject
+
+		geofences, err = listGeofences(client)
+
+		if err == nil {
+			geofence, err = common.FindByName(geofences, config.Name.ValueString(),
+				func(item geofenceObject) string { return item.Name },
+				func(item geofenceObject) int64 { return item.Id },
+			)
+		}
+	}
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error Reading Flespi Geofence",
+			"Could not look up Flespi geofence: "+err.Error(),
+		)
+		return
+	}
+
+	model, diags := convertFlespiGeofenceToDataSourceModel(ctx, geofence)
+
+	response.Diagnostics.Append(diags...)
+	response.Diagnostics.Append(response.State.Set(ctx, model)...)
+}
+
+func (d *gwGeofencesDataSource) Metadata(ctx context.Context, request datasource.MetadataRequest, response *datasource.MetadataResponse) {
+	response.TypeName = request.ProviderTypeName + "_geofences"
+}
+
+func (d *gwGeofencesDataSource) Configure(ctx context.Context, request datasource.ConfigureRequest, response *datasource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	client, ok := request.ProviderData.(*flespi.Client)
+
+	if !ok {
+		response.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *flespi.Client, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *gwGeofencesDataSource) Schema(ctx context.Context, request datasource.SchemaRequest, response *datasource.SchemaResponse) {
+	response.Schema = schema.Schema{
+		Description: "Lists flespi geofences matching all of the given filters.",
+		Attributes: map[string]schema.Attribute{
+			"name_regex": schema.StringAttribute{
+				Optional:    true,
+				Description: "Regular expression the geofence name must match.",
+			},
+			"account_id": schema.Int64Attribute{
+				Optional:    true,
+				Description: "List geofences as this (sub)account and only return the ones it owns.",
+			},
+			"enabled": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Only return enabled (true) or disabled (false) geofences.",
+			},
+			"geofences": schema.ListNestedAttribute{
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: geofenceDataSourceAttributes(),
+				},
+			},
+		},
+	}
+}
+
+func (d *gwGeofencesDataSource) Read(ctx context.Context, request datasource.ReadRequest, response *datasource.ReadResponse) {
+	var config geofencesDataSourceModel
+
+	response.Diagnostics.Append(request.Config.Get(ctx, &config)...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	filter, diags := common.NewListFilter(config.NameRegex, config.AccountId, config.Enabled, types.StringNull(), types.StringNull())
+
+	response.Diagnostics.Append(diags...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	geofences, err := listGeofences(common.ForAccount(d.client, config.AccountId.ValueInt64()))
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error Reading Flespi Geofences",
+			"Could not list Flespi geofences: "+err.Error(),
+		)
+		return
+	}
+
+	config.Geofences = []geofenceDataSourceModel{}
+
+	for _, geofence := range geofences {
+		if !filter.MatchName(geofence.Name) || !filter.MatchAccountId(geofence.AccountId) || !filter.MatchEnabled(geofence.Enabled) {
+			continue
+		}
+
+		model, diags := convertFlespiGeofenceToDataSourceModel(ctx, &geofence)
+
+		response.Diagnostics.Append(diags...)
+
+		if response.Diagnostics.HasError() {
+			return
+		}
+
+		config.Geofences = append(config.Geofences, *model)
+	}
+
+	response.Diagnostics.Append(response.State.Set(ctx, &config)...)
+}
+
+func convertFlespiGeofenceToDataSourceModel(_ context.Context, geofence *geofenceObject) (*geofenceDataSourceModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	geometry := jsontypes.NewNormalizedNull()
+
+	if len(geofence.Geometry) != 0 && string(geofence.Geometry) != "null" {
+		geometry = jsontypes.NewNormalizedValue(string(geofence.Geometry))
+	}
+
+	return &geofenceDataSourceModel{
+		Id:        types.Int64Value(geofence.Id),
+		Name:      types.StringValue(geofence.Name),
+		Enabled:   types.BoolValue(geofence.Enabled),
+		Priority:  types.Int64Value(geofence.Priority),
+		Geometry:  geometry,
+		AccountId: types.Int64Value(geofence.AccountId),
+	}, diags
+}
@@ -0,0 +1,125 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	flespi_geofence "github.com/mixser/flespi-client/resources/gateway/geofence"
+)
+
+var (
+	_ function.Function = &geofenceFromGeoJSONFunction{}
+	_ function.Function = &geofenceFromKMLFunction{}
+)
+
+const geofenceFunctionDescription = "Polygons become `polygon` geometry, LineStrings become `corridor` geometry and Points become `circle` geometry. " +
+	"Corridors and circles take their width and radius, in kilometers, from a `width` or `radius` value on the feature. " +
+	"Polygons and paths with more points than flespi accepts are simplified. The result is JSON for the `geometry` attribute of `flespi_geofence`."
+
+func NewGeofenceFromGeoJSONFunction() function.Function {
+	return &geofenceFromGeoJSONFunction{}
+}
+
+type geofenceFromGeoJSONFunction struct{}
+
+func (f *geofenceFromGeoJSONFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "geofence_from_geojson"
+}
+
+func (f *geofenceFromGeoJSONFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Convert a GeoJSON Feature into flespi geofence geometry",
+		MarkdownDescription: "Converts a GeoJSON Feature, or a bare GeoJSON geometry, into flespi geofence geometry. " + geofenceFunctionDescription,
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "geojson",
+				MarkdownDescription: "GeoJSON Feature, e.g. `file(\"area.geojson\")` or `jsonencode(jsondecode(file(\"areas.geojson\")).features[0])`",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *geofenceFromGeoJSONFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var document string
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &document))
+
+	if resp.Error != nil {
+		return
+	}
+
+	geometry, err := geometryFromGeoJSON(document)
+
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	resp.Error = setGeometryResult(ctx, resp, geometry)
+}
+
+func NewGeofenceFromKMLFunction() function.Function {
+	return &geofenceFromKMLFunction{}
+}
+
+type geofenceFromKMLFunction struct{}
+
+func (f *geofenceFromKMLFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "geofence_from_kml"
+}
+
+func (f *geofenceFromKMLFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Convert a KML Placemark into flespi geofence geometry",
+		MarkdownDescription: "Converts a KML Placemark into flespi geofence geometry. " + geofenceFunctionDescription + " Widths and radii are read from the Placemark's `ExtendedData`.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "kml",
+				MarkdownDescription: "KML document, e.g. `file(\"areas.kml\")`",
+			},
+			function.StringParameter{
+				Name:                "placemark",
+				MarkdownDescription: "Name of the Placemark to convert, or `\"\"` when the document holds a single Placemark",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *geofenceFromKMLFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var document, name string
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &document, &name))
+
+	if resp.Error != nil {
+		return
+	}
+
+	geometry, err := geometryFromKML(document, name)
+
+	if err != nil {
+		argument := int64(0)
+
+		if errors.As(err, &placemarkError{}) {
+			argument = 1
+		}
+
+		resp.Error = function.NewArgumentFuncError(argument, err.Error())
+		return
+	}
+
+	resp.Error = setGeometryResult(ctx, resp, geometry)
+}
+
+func setGeometryResult(ctx context.Context, resp *function.RunResponse, geometry flespi_geofence.GeofenceGeometry) *function.FuncError {
+	encoded, err := json.Marshal(geometry)
+
+	if err != nil {
+		return function.NewFuncError(err.Error())
+	}
+
+	return resp.Result.Set(ctx, string(encoded))
+}
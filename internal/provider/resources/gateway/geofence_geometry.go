@@ -0,0 +1,97 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+
+	flespi_geofence "github.com/mixser/flespi-client/resources/gateway/geofence"
+)
+
+// parseGeometry decodes geofence geometry JSON by its type, unlike
+// flespi_geofence.UnmarshalGeometry which takes anything for a circle, and
+// checks that the geometry is usable.
+func parseGeometry(raw json.RawMessage) (flespi_geofence.GeofenceGeometry, error) {
+	var header struct {
+		Type string `json:"type"`
+	}
+
+	if err := json.Unmarshal(raw, &header); err != nil {
+		return nil, err
+	}
+
+	var geometry flespi_geofence.GeofenceGeometry
+
+	switch header.Type {
+	case "circle":
+		geometry = &flespi_geofence.Circle{}
+	case "polygon":
+		geometry = &flespi_geofence.Polygon{}
+	case "corridor":
+		geometry = &flespi_geofence.Corridor{}
+	default:
+		return nil, fmt.Errorf("unsupported geometry type %q, expected \"circle\", \"polygon\" or \"corridor\"", header.Type)
+	}
+
+	if err := json.Unmarshal(raw, geometry); err != nil {
+		return nil, err
+	}
+
+	if err := checkGeometry(geometry); err != nil {
+		return nil, err
+	}
+
+	return geometry, nil
+}
+
+// checkGeometry reports the first problem of geometry: coordinates out of
+// range, too few points or a non-positive radius or width.
+func checkGeometry(geometry flespi_geofence.GeofenceGeometry) error {
+	switch typed := geometry.(type) {
+	case *flespi_geofence.Circle:
+		if typed.Radius <= 0 {
+			return fmt.Errorf("circle radius must be positive, got %g", typed.Radius)
+		}
+
+		return checkPoint("center", typed.Center)
+	case *flespi_geofence.Polygon:
+		if len(typed.Path) < 3 {
+			return fmt.Errorf("polygon needs at least 3 points, got %d", len(typed.Path))
+		}
+
+		return checkPoints("path", typed.Path)
+	case *flespi_geofence.Corridor:
+		if typed.Width <= 0 {
+			return fmt.Errorf("corridor width must be positive, got %g", typed.Width)
+		}
+
+		if len(typed.Path) < 2 {
+			return fmt.Errorf("corridor needs at least 2 points, got %d", len(typed.Path))
+		}
+
+		return checkPoints("path", typed.Path)
+	default:
+		return fmt.Errorf("unsupported geometry type %q", geometry.GetType())
+	}
+}
+
+func checkPoints(name string, points []flespi_geofence.Point) error {
+	for i, point := range points {
+		if err := checkPoint(fmt.Sprintf("%s[%d]", name, i), point); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func checkPoint(name string, point flespi_geofence.Point) error {
+	if point.Latitude < -90 || point.Latitude > 90 {
+		return fmt.Errorf("%s: latitude %g is out of range [-90, 90]", name, point.Latitude)
+	}
+
+	if point.Longitude < -180 || point.Longitude > 180 {
+		return fmt.Errorf("%s: longitude %g is out of range [-180, 180]", name, point.Longitude)
+	}
+
+	return nil
+}
@@ -0,0 +1,742 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"terraform-provider-flespi/internal/provider/resources/common"
+
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	flespi "github.com/mixser/flespi-client"
+)
+
+var (
+	_ resource.Resource                   = &gwGeofenceSetResource{}
+	_ resource.ResourceWithConfigure      = &gwGeofenceSetResource{}
+	_ resource.ResourceWithImportState    = &gwGeofenceSetResource{}
+	_ resource.ResourceWithValidateConfig = &gwGeofenceSetResource{}
+)
+
+// Geofences of a set carry the set name and their feature key in metadata,
+// which is how the set finds them again.
+const (
+	geofenceSetMetadataKey = "terraform_geofence_set"
+	geofenceKeyMetadataKey = "terraform_geofence_key"
+)
+
+// gwGeofenceSetResource manages one geofence per feature of a GeoJSON
+// FeatureCollection. It is meant for large numbers of geofences, which as
+// separate flespi_geofence resources make plans slow and state large.
+type gwGeofenceSetResource struct {
+	provider *flespi.Client
+}
+
+type geofenceSetResourceModel struct {
+	ID          types.String         `tfsdk:"id"`
+	Name        types.String         `tfsdk:"name"`
+	Features    jsontypes.Normalized `tfsdk:"features"`
+	KeyProperty types.String         `tfsdk:"key_property"`
+	Geofences   types.Map            `tfsdk:"geofences"`
+	AccountId   types.Int64          `tfsdk:"account_id"`
+}
+
+// geofenceSetFeature is a feature of the collection and the geofence it
+// stands for.
+type geofenceSetFeature struct {
+	key      string
+	raw      json.RawMessage
+	geofence geofenceObject
+}
+
+func NewGeofenceSetResource() resource.Resource {
+	return &gwGeofenceSetResource{}
+}
+
+func (g *gwGeofenceSetResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_geofence_set"
+}
+
+func (g *gwGeofenceSetResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages one geofence per feature of a GeoJSON FeatureCollection. " +
+			"Geofences are matched to features by a stable key stored in their metadata, so only the features that changed are written on apply. " +
+			"New geofences are created and removed ones deleted in batches of 100, while each changed geofence is written with its own request, as flespi has no batch update that takes a different body per geofence. " +
+			"If creating the set fails part way, the geofences created so far are deleted again.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the set, stored in the metadata of its geofences. Must be unique per account.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"features": schema.StringAttribute{
+				CustomType: jsontypes.NormalizedType{},
+				Required:   true,
+				MarkdownDescription: "GeoJSON FeatureCollection, e.g. `file(\"depots.geojson\")`. Each feature becomes a geofence named after its `name` property, with the priority in its `priority` property. " +
+					"Geometry is converted as by the `geofence_from_geojson` function.",
+			},
+			"key_property": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("id"),
+				MarkdownDescription: "Feature property holding the stable key of each feature. Features without it fall back to their GeoJSON `id`. Defaults to `id`.",
+			},
+			"geofences": schema.MapAttribute{
+				ElementType: types.Int64Type,
+				Computed:    true,
+				Description: "Geofence IDs by feature key.",
+			},
+			"account_id": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Subaccount ID to create the geofences under.",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+		},
+	}
+}
+
+// ValidateConfig converts the features during plan so that bad geometry,
+// missing keys and duplicates are reported before anything is written.
+func (g *gwGeofenceSetResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data geofenceSetResourceModel
+
+	if req.Config.Get(ctx, &data).HasError() || data.Features.IsNull() || data.Features.IsUnknown() || data.KeyProperty.IsUnknown() {
+		return
+	}
+
+	if _, err := parseGeofenceSetFeatures(data.Features.ValueString(), data.keyProperty()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("features"),
+			"Invalid Geofence Features",
+			err.Error(),
+		)
+	}
+}
+
+func (g *gwGeofenceSetResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*flespi.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *flespi.Client, got: %T", req.ProviderData),
+		)
+		return
+	}
+
+	g.provider = client
+}
+
+func (g *gwGeofenceSetResource) Create(ctx context.Context, request resource.CreateRequest, response *resource.CreateResponse) {
+	var plan geofenceSetResourceModel
+
+	response.Diagnostics.Append(request.Plan.Get(ctx, &plan)...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	result, diags := g.apply(ctx, plan, true)
+
+	response.Diagnostics.Append(diags...)
+
+	if result != nil {
+		response.Diagnostics.Append(response.State.Set(ctx, result)...)
+	}
+}
+
+func (g *gwGeofenceSetResource) Read(ctx context.Context, request resource.ReadRequest, response *resource.ReadResponse) {
+	var state geofenceSetResourceModel
+
+	response.Diagnostics.Append(request.State.Get(ctx, &state)...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	members, err := listGeofenceSet(common.ForAccount(g.provider, state.AccountId.ValueInt64()), state.Name.ValueString())
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Failed to read geofence set",
+			fmt.Sprintf("Error listing geofences: %s", err),
+		)
+		return
+	}
+
+	byKey, _ := geofencesByKey(members)
+
+	features, err := syncGeofenceSetFeatures(state.Features, state.keyProperty(), byKey)
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Failed to read geofence set",
+			fmt.Sprintf("Error converting geofences to GeoJSON: %s", err),
+		)
+		return
+	}
+
+	state.Features = features
+	state.KeyProperty = types.StringValue(state.keyProperty())
+	state.ID = state.Name
+
+	if len(members) > 0 {
+		state.AccountId = types.Int64Value(members[0].AccountId)
+	} else if state.AccountId.IsNull() || state.AccountId.IsUnknown() {
+		state.AccountId = types.Int64Value(0)
+	}
+
+	geofences, diags := geofenceSetIds(ctx, byKey)
+
+	response.Diagnostics.Append(diags...)
+	state.Geofences = geofences
+
+	response.Diagnostics.Append(response.State.Set(ctx, &state)...)
+}
+
+func (g *gwGeofenceSetResource) Update(ctx context.Context, request resource.UpdateRequest, response *resource.UpdateResponse) {
+	var plan geofenceSetResourceModel
+
+	response.Diagnostics.Append(request.Plan.Get(ctx, &plan)...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	result, diags := g.apply(ctx, plan, false)
+
+	response.Diagnostics.Append(diags...)
+
+	if result != nil {
+		response.Diagnostics.Append(response.State.Set(ctx, result)...)
+	}
+}
+
+func (g *gwGeofenceSetResource) Delete(ctx context.Context, request resource.DeleteRequest, response *resource.DeleteResponse) {
+	var state geofenceSetResourceModel
+
+	response.Diagnostics.Append(request.State.Get(ctx, &state)...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	client := common.ForAccount(g.provider, state.AccountId.ValueInt64())
+
+	members, err := listGeofenceSet(client, state.Name.ValueString())
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Failed to delete geofence set",
+			fmt.Sprintf("Error listing geofences: %s", err),
+		)
+		return
+	}
+
+	ids := make([]int64, 0, len(members))
+
+	for _, member := range members {
+		ids = append(ids, member.Id)
+	}
+
+	if err := deleteGeofences(client, ids); err != nil {
+		response.Diagnostics.AddError(
+			"Failed to delete geofence set",
+			fmt.Sprintf("Error deleting geofences: %s", err),
+		)
+	}
+}
+
+// ImportState takes over the geofences already tagged with the set name. It
+// accepts "name" or "account_id/name".
+func (g *gwGeofenceSetResource) ImportState(ctx context.Context, request resource.ImportStateRequest, response *resource.ImportStateResponse) {
+	name := strings.TrimSpace(request.ID)
+	var accountId int64
+
+	if account, rest, found := strings.Cut(name, "/"); found {
+		id, err := strconv.ParseInt(account, 10, 64)
+
+		if err != nil || rest == "" {
+			response.Diagnostics.AddError(
+				"Invalid Import ID",
+				fmt.Sprintf("Expected \"name\" or \"account_id/name\", got: %q", request.ID),
+			)
+			return
+		}
+
+		accountId, name = id, rest
+	}
+
+	response.Diagnostics.Append(response.State.SetAttribute(ctx, path.Root("id"), name)...)
+	response.Diagnostics.Append(response.State.SetAttribute(ctx, path.Root("name"), name)...)
+
+	if accountId != 0 {
+		response.Diagnostics.Append(response.State.SetAttribute(ctx, path.Root("account_id"), accountId)...)
+	}
+}
+
+// apply makes the geofences of the set match the planned features: missing
+// ones are created in batches, changed ones updated and the rest deleted in
+// batches. Geofences that already match are not touched. When creating, an
+// account that already has geofences tagged with the set name is an error
+// rather than a set to take over.
+func (g *gwGeofenceSetResource) apply(ctx context.Context, plan geofenceSetResourceModel, creating bool) (*geofenceSetResourceModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	features, err := parseGeofenceSetFeatures(plan.Features.ValueString(), plan.keyProperty())
+
+	if err != nil {
+		diags.AddAttributeError(path.Root("features"), "Invalid Geofence Features", err.Error())
+		return nil, diags
+	}
+
+	setName := plan.Name.ValueString()
+	accountId := plan.AccountId.ValueInt64()
+	client := common.ForAccount(g.provider, accountId)
+
+	members, err := listGeofenceSet(client, setName)
+
+	if err != nil {
+		diags.AddError("Failed to apply geofence set", fmt.Sprintf("Error listing geofences: %s", err))
+		return nil, diags
+	}
+
+	if creating && len(members) > 0 {
+		diags.AddAttributeError(
+			path.Root("name"),
+			"Geofence Set Already Exists",
+			fmt.Sprintf("%d geofences of this account already belong to a set named %q. Choose another name, or import the existing set with terraform import.", len(members), setName),
+		)
+		return nil, diags
+	}
+
+	byKey, duplicates := geofencesByKey(members)
+	ids := make(map[string]int64, len(features))
+	wanted := make(map[string]bool, len(features))
+
+	var created []geofenceObject
+	var createdKeys []string
+	updated := 0
+
+	for _, feature := range features {
+		wanted[feature.key] = true
+		desired := feature.geofence
+
+		current, ok := byKey[feature.key]
+
+		if !ok {
+			desired.Metadata = &map[string]string{
+				geofenceSetMetadataKey: setName,
+				geofenceKeyMetadataKey: feature.key,
+			}
+
+			created = append(created, desired)
+			createdKeys = append(createdKeys, feature.key)
+			continue
+		}
+
+		ids[feature.key] = current.Id
+
+		if geofenceMatches(current, desired) {
+			continue
+		}
+
+		metadata := make(map[string]string, len(current.metadata()))
+
+		for key, value := range current.metadata() {
+			metadata[key] = value
+		}
+
+		desired.Id = current.Id
+		desired.AccountId = accountId
+		desired.Metadata = &metadata
+
+		if err := updateGeofence(g.provider, desired); err != nil {
+			diags.AddError("Failed to apply geofence set", fmt.Sprintf("Error updating geofence %q: %s", feature.key, err))
+			return nil, diags
+		}
+
+		updated++
+	}
+
+	removed := duplicates
+
+	for key, member := range byKey {
+		if !wanted[key] {
+			removed = append(removed, member.Id)
+		}
+	}
+
+	tflog.Debug(ctx, "Applying flespi geofence set", map[string]interface{}{
+		"name":    setName,
+		"create":  len(created),
+		"update":  updated,
+		"delete":  len(removed),
+		"members": len(members),
+	})
+
+	if err := deleteGeofences(client, removed); err != nil {
+		diags.AddError("Failed to apply geofence set", fmt.Sprintf("Error deleting geofences: %s", err))
+		return nil, diags
+	}
+
+	result, err := createGeofences(g.provider, accountId, created)
+
+	for i, geofence := range result {
+		ids[createdKeys[i]] = geofence.Id
+	}
+
+	if err != nil {
+		diags.AddError("Failed to apply geofence set", fmt.Sprintf("Error creating geofences: %s", err))
+
+		// A failed create leaves no state behind, so the geofences written
+		// so far would block the next attempt as a taken set name. Delete them.
+		if creating {
+			createdIds := make([]int64, 0, len(result))
+
+			for _, geofence := range result {
+				createdIds = append(createdIds, geofence.Id)
+			}
+
+			if err := deleteGeofences(client, createdIds); err != nil {
+				diags.AddError("Failed to roll back geofence set", fmt.Sprintf("Error deleting the %d geofences created so far: %s", len(createdIds), err))
+			}
+		}
+
+		return nil, diags
+	}
+
+	geofences, mapDiags := types.MapValueFrom(ctx, types.Int64Type, ids)
+	diags.Append(mapDiags...)
+
+	plan.ID = plan.Name
+	plan.Geofences = geofences
+
+	if plan.AccountId.IsUnknown() || plan.AccountId.IsNull() {
+		switch {
+		case len(members) > 0:
+			plan.AccountId = types.Int64Value(members[0].AccountId)
+		case len(result) > 0:
+			plan.AccountId = types.Int64Value(result[0].AccountId)
+		default:
+			plan.AccountId = types.Int64Value(accountId)
+		}
+	}
+
+	return &plan, diags
+}
+
+func (data geofenceSetResourceModel) keyProperty() string {
+	if data.KeyProperty.IsNull() || data.KeyProperty.IsUnknown() {
+		return "id"
+	}
+
+	return data.KeyProperty.ValueString()
+}
+
+// listGeofenceSet returns the geofences tagged with the set name.
+func listGeofenceSet(client *flespi.Client, setName string) ([]geofenceObject, error) {
+	geofences, err := listGeofencesWithMetadata(client)
+
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]geofenceObject, 0)
+
+	for _, geofence := range geofences {
+		if geofence.metadata()[geofenceSetMetadataKey] == setName {
+			members = append(members, geofence)
+		}
+	}
+
+	return members, nil
+}
+
+// geofencesByKey indexes set members by feature key. Geofences sharing a key
+// with an earlier one, e.g. left by an interrupted apply, are returned as
+// duplicates to be deleted.
+func geofencesByKey(members []geofenceObject) (map[string]geofenceObject, []int64) {
+	byKey := make(map[string]geofenceObject, len(members))
+	var duplicates []int64
+
+	for _, member := range members {
+		key := member.metadata()[geofenceKeyMetadataKey]
+
+		if _, ok := byKey[key]; ok {
+			duplicates = append(duplicates, member.Id)
+			continue
+		}
+
+		byKey[key] = member
+	}
+
+	return byKey, duplicates
+}
+
+func geofenceSetIds(ctx context.Context, byKey map[string]geofenceObject) (types.Map, diag.Diagnostics) {
+	ids := make(map[string]int64, len(byKey))
+
+	for key, geofence := range byKey {
+		ids[key] = geofence.Id
+	}
+
+	return types.MapValueFrom(ctx, types.Int64Type, ids)
+}
+
+// geofenceMatches reports whether the remote geofence already is what the
+// feature describes. Geometry fields flespi adds on its own are ignored.
+func geofenceMatches(remote, desired geofenceObject) bool {
+	return remote.Name == desired.Name &&
+		remote.Priority == desired.Priority &&
+		remote.Enabled == desired.Enabled &&
+		common.JSONContains(string(remote.Geometry), string(desired.Geometry))
+}
+
+// parseGeofenceSetFeatures converts a FeatureCollection into the geofences it
+// describes, keyed by the keyProperty property or the feature's own id.
+func parseGeofenceSetFeatures(document, keyProperty string) ([]geofenceSetFeature, error) {
+	var collection struct {
+		Type     string            `json:"type"`
+		Features []json.RawMessage `json:"features"`
+	}
+
+	if err := json.Unmarshal([]byte(document), &collection); err != nil {
+		return nil, fmt.Errorf("invalid GeoJSON: %w", err)
+	}
+
+	if collection.Type != "FeatureCollection" {
+		return nil, fmt.Errorf("expected a GeoJSON FeatureCollection, got %q", collection.Type)
+	}
+
+	features := make([]geofenceSetFeature, 0, len(collection.Features))
+	seen := make(map[string]int, len(collection.Features))
+
+	for i, raw := range collection.Features {
+		var feature geoJSONObject
+
+		if err := json.Unmarshal(raw, &feature); err != nil {
+			return nil, fmt.Errorf("feature %d: %w", i, err)
+		}
+
+		key, err := geoJSONKey(feature, keyProperty)
+
+		if err != nil {
+			return nil, fmt.Errorf("feature %d: %w", i, err)
+		}
+
+		if first, ok := seen[key]; ok {
+			return nil, fmt.Errorf("features %d and %d share the key %q", first, i, key)
+		}
+
+		seen[key] = i
+
+		geofence, err := geoJSONGeofence(feature, key)
+
+		if err != nil {
+			return nil, fmt.Errorf("feature %q: %w", key, err)
+		}
+
+		features = append(features, geofenceSetFeature{key: key, raw: raw, geofence: geofence})
+	}
+
+	return features, nil
+}
+
+func geoJSONKey(feature geoJSONObject, keyProperty string) (string, error) {
+	value, ok := feature.Properties[keyProperty]
+
+	if !ok || value == nil {
+		value = feature.Id
+	}
+
+	switch typed := value.(type) {
+	case string:
+		if typed != "" {
+			return typed, nil
+		}
+	case float64:
+		return strconv.FormatFloat(typed, 'f', -1, 64), nil
+	}
+
+	return "", fmt.Errorf("needs a string or numeric %q property or feature id to key it by", keyProperty)
+}
+
+func geoJSONGeofence(feature geoJSONObject, key string) (geofenceObject, error) {
+	if feature.Type != "Feature" {
+		return geofenceObject{}, fmt.Errorf("expected a Feature, got %q", feature.Type)
+	}
+
+	if feature.Geometry == nil {
+		return geofenceObject{}, fmt.Errorf("feature has no geometry")
+	}
+
+	geofence := geofenceObject{Name: key, Enabled: true}
+
+	switch name := feature.Properties["name"].(type) {
+	case nil:
+	case string:
+		geofence.Name = name
+	default:
+		return geofenceObject{}, fmt.Errorf("\"name\" property must be a string")
+	}
+
+	switch priority := feature.Properties["priority"].(type) {
+	case nil:
+	case float64:
+		if priority != float64(int64(priority)) {
+			return geofenceObject{}, fmt.Errorf("\"priority\" property must be an integer, got %g", priority)
+		}
+
+		geofence.Priority = int64(priority)
+	default:
+		return geofenceObject{}, fmt.Errorf("\"priority\" property must be a number")
+	}
+
+	geometry, err := geoJSONGeometry(*feature.Geometry, feature.Properties)
+
+	if err != nil {
+		return geofenceObject{}, err
+	}
+
+	if geofence.Geometry, err = json.Marshal(geometry); err != nil {
+		return geofenceObject{}, err
+	}
+
+	return geofence, nil
+}
+
+// geofenceToFeature renders a set member as a GeoJSON Feature, for geofences
+// that drifted or were imported.
+func geofenceToFeature(geofence geofenceObject, key, keyProperty string) (json.RawMessage, error) {
+	geometry, err := parseGeometry(geofence.Geometry)
+
+	if err != nil {
+		return nil, fmt.Errorf("geofence %q: %w", key, err)
+	}
+
+	object, properties := geometryToGeoJSON(geometry)
+
+	properties[keyProperty] = key
+	properties["name"] = geofence.Name
+	properties["priority"] = geofence.Priority
+
+	return json.Marshal(map[string]interface{}{
+		"type":       "Feature",
+		"properties": properties,
+		"geometry":   object,
+	})
+}
+
+// syncGeofenceSetFeatures returns the features to keep in state for the set
+// members in byKey. Features whose geofence still matches keep their
+// configured form; drifted ones are replaced by what flespi holds, deleted
+// ones are dropped and geofences added outside of Terraform are appended, so
+// the plan shows exactly the features that will be rewritten. Without prior
+// features, after an import, every member is rendered.
+func syncGeofenceSetFeatures(prior jsontypes.Normalized, keyProperty string, byKey map[string]geofenceObject) (jsontypes.Normalized, error) {
+	var features []geofenceSetFeature
+
+	if !prior.IsNull() && !prior.IsUnknown() {
+		parsed, err := parseGeofenceSetFeatures(prior.ValueString(), keyProperty)
+
+		if err != nil {
+			// Only state written before the features became invalid can get
+			// here; keep it and let the next apply report the problem.
+			return prior, nil
+		}
+
+		features = parsed
+	}
+
+	changed := prior.IsNull() || prior.IsUnknown()
+	result := make([]json.RawMessage, 0, len(byKey))
+	known := make(map[string]bool, len(features))
+
+	for _, feature := range features {
+		known[feature.key] = true
+		remote, ok := byKey[feature.key]
+
+		if !ok {
+			changed = true
+			continue
+		}
+
+		if geofenceMatches(remote, feature.geofence) {
+			result = append(result, feature.raw)
+			continue
+		}
+
+		raw, err := geofenceToFeature(remote, feature.key, keyProperty)
+
+		if err != nil {
+			return prior, err
+		}
+
+		result = append(result, raw)
+		changed = true
+	}
+
+	extra := make([]string, 0)
+
+	for key := range byKey {
+		if !known[key] {
+			extra = append(extra, key)
+		}
+	}
+
+	sort.Strings(extra)
+
+	for _, key := range extra {
+		raw, err := geofenceToFeature(byKey[key], key, keyProperty)
+
+		if err != nil {
+			return prior, err
+		}
+
+		result = append(result, raw)
+		changed = true
+	}
+
+	if !changed {
+		return prior, nil
+	}
+
+	document, err := json.Marshal(map[string]interface{}{
+		"type":     "FeatureCollection",
+		"features": result,
+	})
+
+	if err != nil {
+		return prior, err
+	}
+
+	return jsontypes.NewNormalizedValue(string(document)), nil
+}
@@ -0,0 +1,420 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-flespi/internal/provider/resources/common"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	flespi "github.com/mixser/flespi-client"
+)
+
+var (
+	_ resource.Resource                     = &gwGroupResource{}
+	_ resource.ResourceWithConfigure        = &gwGroupResource{}
+	_ resource.ResourceWithImportState      = &gwGroupResource{}
+	_ resource.ResourceWithConfigValidators = &gwGroupResource{}
+)
+
+type gwGroupResource struct {
+	provider *flespi.Client
+}
+
+type groupResourceModel struct {
+	Id       types.Int64  `tfsdk:"id"`
+	Name     types.String `tfsdk:"name"`
+	ItemType types.String `tfsdk:"item_type"`
+
+	Selector types.String `tfsdk:"selector"`
+	ItemIds  types.Set    `tfsdk:"item_ids"`
+
+	Metadata  types.Map   `tfsdk:"metadata"`
+	AccountId types.Int64 `tfsdk:"account_id"`
+
+	PreserveUnmanagedMetadata types.Bool `tfsdk:"preserve_unmanaged_metadata"`
+}
+
+func NewGroupResource() resource.Resource {
+	return &gwGroupResource{}
+}
+
+func (g *gwGroupResource) Metadata(ctx context.Context, request resource.MetadataRequest, response *resource.MetadataResponse) {
+	response.TypeName = request.ProviderTypeName + "_group"
+}
+
+func (g *gwGroupResource) Configure(ctx context.Context, request resource.ConfigureRequest, response *resource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	client, ok := request.ProviderData.(*flespi.Client)
+
+	if !ok {
+		response.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *flespi.Client, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+		)
+
+		return
+	}
+
+	g.provider = client
+}
+
+func (g *gwGroupResource) Schema(ctx context.Context, request resource.SchemaRequest, response *resource.SchemaResponse) {
+	response.Schema = schema.Schema{
+		Description: "Group of gateway entities, usable as a single target for stream subscriptions and calculator or plugin assignments.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.Int64Attribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the group",
+			},
+			"item_type": schema.StringAttribute{
+				Required:    true,
+				Description: "Type of the entities in the group. Changing it recreates the group.",
+				Validators: []validator.String{
+					stringvalidator.OneOf("devices", "channels", "streams", "calcs", "plugins", "geofences"),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"selector": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Expression selecting the members of the group, e.g. \"metadata.fleet=north\". Conflicts with item_ids.",
+			},
+			"item_ids": schema.SetAttribute{
+				Optional:    true,
+				ElementType: types.Int64Type,
+				Description: "Explicit IDs of the group members. When set, members added outside of Terraform show up as drift. Conflicts with selector.",
+			},
+			"metadata": schema.MapAttribute{
+				Optional:    true,
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Group metadata",
+			},
+			"account_id": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Subaccount ID to create the group under. Changing it recreates the group.",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"preserve_unmanaged_metadata": common.PreserveUnmanagedMetadataAttribute(),
+		},
+	}
+}
+
+func (g *gwGroupResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.Conflicting(
+			path.MatchRoot("selector"),
+			path.MatchRoot("item_ids"),
+		),
+	}
+}
+
+func (g *gwGroupResource) Create(ctx context.Context, request resource.CreateRequest, response *resource.CreateResponse) {
+	var data *groupResourceModel
+
+	response.Diagnostics.Append(request.Plan.Get(ctx, &data)...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	instance := g.convertResourceModelToFlespiGroup(ctx, *data)
+
+	group, err := createGroup(g.provider, instance)
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Failed to create group",
+			fmt.Sprintf("Error creating group: %s", err),
+		)
+		return
+	}
+
+	client := common.ForAccount(g.provider, data.AccountId.ValueInt64())
+
+	if !data.ItemIds.IsNull() {
+		var itemIds []int64
+
+		response.Diagnostics.Append(data.ItemIds.ElementsAs(ctx, &itemIds, false)...)
+
+		if response.Diagnostics.HasError() {
+			return
+		}
+
+		if err := addGroupItems(client, *group, itemIds); err != nil {
+			response.Diagnostics.AddError(
+				"Failed to add group items",
+				fmt.Sprintf("Error adding %s to group %d: %s", group.ItemType, group.Id, err),
+			)
+
+			// The group is not in state yet, so remove it rather than leave it
+			// behind for the next apply to create again.
+			if err := deleteGroup(client, group.Id); err != nil && !common.IsNotFound(err) {
+				response.Diagnostics.AddError(
+					"Failed to delete partially created group",
+					fmt.Sprintf("Group %d was created but could not be deleted, remove it manually or import it: %s", group.Id, err),
+				)
+			}
+
+			return
+		}
+	}
+
+	group, err = getGroup(client, group.Id)
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Failed to get group after creation",
+			fmt.Sprintf("Error reading group: %s", err),
+		)
+		return
+	}
+
+	result, diags := g.convertFlespiGroupToResourceModel(ctx, client, group, data.ItemIds, data.Metadata, data.PreserveUnmanagedMetadata)
+
+	response.Diagnostics.Append(diags...)
+	response.Diagnostics.Append(response.State.Set(ctx, &result)...)
+}
+
+func (g *gwGroupResource) Read(ctx context.Context, request resource.ReadRequest, response *resource.ReadResponse) {
+	var state groupResourceModel
+
+	diags := request.State.Get(ctx, &state)
+	response.Diagnostics.Append(diags...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	client := common.ForAccount(g.provider, state.AccountId.ValueInt64())
+
+	group, err := getGroup(client, state.Id.ValueInt64())
+
+	if common.IsNotFound(err) {
+		tflog.Warn(ctx, "Flespi group not found, removing from state", map[string]interface{}{"id": state.Id.ValueInt64()})
+		response.State.RemoveResource(ctx)
+		return
+	}
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error Reading Flespi Group",
+			"Could not read Flespi group ID "+state.Id.String()+": "+err.Error(),
+		)
+
+		return
+	}
+
+	model, diags := g.convertFlespiGroupToResourceModel(ctx, client, group, state.ItemIds, state.Metadata, state.PreserveUnmanagedMetadata)
+
+	response.Diagnostics.Append(diags...)
+	response.Diagnostics.Append(response.State.Set(ctx, model)...)
+}
+
+func (g *gwGroupResource) Update(ctx context.Context, request resource.UpdateRequest, response *resource.UpdateResponse) {
+	var plan groupResourceModel
+	var state groupResourceModel
+
+	response.Diagnostics.Append(request.Plan.Get(ctx, &plan)...)
+	response.Diagnostics.Append(request.State.Get(ctx, &state)...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Id = state.Id
+
+	client := common.ForAccount(g.provider, state.AccountId.ValueInt64())
+
+	var group = g.convertResourceModelToFlespiGroup(ctx, plan)
+
+	group.AccountId = state.AccountId.ValueInt64()
+
+	if plan.PreserveUnmanagedMetadata.ValueBool() && group.Metadata != nil {
+		current, err := getGroup(client, state.Id.ValueInt64())
+
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Error Reading Flespi Group",
+				"Could not read current group metadata: "+err.Error(),
+			)
+			return
+		}
+
+		prior, _, diags := common.MetadataElements(ctx, state.Metadata)
+		response.Diagnostics.Append(diags...)
+
+		metadata := common.MetadataToSend(*group.Metadata, prior, current.metadata(), true)
+		group.Metadata = &metadata
+	}
+
+	updatedGroup, err := updateGroup(g.provider, group)
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error Updating Flespi Group",
+			"Could not update group, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	if !plan.ItemIds.IsNull() {
+		var itemIds []int64
+
+		response.Diagnostics.Append(plan.ItemIds.ElementsAs(ctx, &itemIds, false)...)
+
+		if response.Diagnostics.HasError() {
+			return
+		}
+
+		current, err := listGroupItems(client, *updatedGroup)
+
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Error Reading Flespi Group",
+				fmt.Sprintf("Could not list %s of group %d: %s", updatedGroup.ItemType, updatedGroup.Id, err),
+			)
+			return
+		}
+
+		added, removed := diffIds(current, itemIds)
+
+		if err := addGroupItems(client, *updatedGroup, added); err != nil {
+			response.Diagnostics.AddError(
+				"Error Updating Flespi Group",
+				fmt.Sprintf("Could not add %s to group %d: %s", updatedGroup.ItemType, updatedGroup.Id, err),
+			)
+			return
+		}
+
+		if err := removeGroupItems(client, *updatedGroup, removed); err != nil {
+			response.Diagnostics.AddError(
+				"Error Updating Flespi Group",
+				fmt.Sprintf("Could not remove %s from group %d: %s", updatedGroup.ItemType, updatedGroup.Id, err),
+			)
+			return
+		}
+	}
+
+	updatedGroup, err = getGroup(client, state.Id.ValueInt64())
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error Reading Flespi Group",
+			"Could not read group Id: "+state.Id.String()+": "+err.Error(),
+		)
+		return
+	}
+
+	model, diags := g.convertFlespiGroupToResourceModel(ctx, client, updatedGroup, plan.ItemIds, common.OwnedMetadata(plan.Metadata, state.Metadata), plan.PreserveUnmanagedMetadata)
+
+	response.Diagnostics.Append(diags...)
+	response.Diagnostics.Append(response.State.Set(ctx, model)...)
+}
+
+func (g *gwGroupResource) Delete(ctx context.Context, request resource.DeleteRequest, response *resource.DeleteResponse) {
+	var state groupResourceModel
+
+	diags := request.State.Get(ctx, &state)
+
+	response.Diagnostics.Append(diags...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	err := deleteGroup(common.ForAccount(g.provider, state.AccountId.ValueInt64()), state.Id.ValueInt64())
+
+	if err != nil && !common.IsNotFound(err) {
+		response.Diagnostics.AddError(
+			"Error Deleting Flespi Group",
+			"Could not delete group, unexpected error: "+err.Error(),
+		)
+		return
+	}
+}
+
+func (g *gwGroupResource) ImportState(ctx context.Context, request resource.ImportStateRequest, response *resource.ImportStateResponse) {
+	common.ImportStateByAccountScopedId(ctx, request, response)
+}
+
+func (g *gwGroupResource) convertResourceModelToFlespiGroup(ctx context.Context, data groupResourceModel) groupObject {
+	var metadata *map[string]string
+
+	if elements, ok, _ := common.MetadataElements(ctx, data.Metadata); ok {
+		metadata = &elements
+	}
+
+	return groupObject{
+		Id:        data.Id.ValueInt64(),
+		Name:      data.Name.ValueString(),
+		ItemType:  data.ItemType.ValueString(),
+		Selector:  data.Selector.ValueString(),
+		Metadata:  metadata,
+		AccountId: data.AccountId.ValueInt64(),
+	}
+}
+
+// convertFlespiGroupToResourceModel builds the state of group. The members are
+// only read when itemIds shows that Terraform manages them.
+func (g *gwGroupResource) convertFlespiGroupToResourceModel(ctx context.Context, client *flespi.Client, group *groupObject, itemIds types.Set, managed types.Map, preserveUnmanaged types.Bool) (*groupResourceModel, diag.Diagnostics) {
+	var state groupResourceModel
+	var diags diag.Diagnostics
+
+	state.Id = types.Int64Value(group.Id)
+	state.Name = types.StringValue(group.Name)
+	state.ItemType = types.StringValue(group.ItemType)
+	state.Selector = types.StringValue(group.Selector)
+	state.ItemIds = types.SetNull(types.Int64Type)
+
+	if !itemIds.IsNull() {
+		ids, err := listGroupItems(client, *group)
+
+		if err != nil {
+			diags.AddError(
+				"Error Reading Flespi Group",
+				fmt.Sprintf("Could not list %s of group %d: %s", group.ItemType, group.Id, err),
+			)
+			return &state, diags
+		}
+
+		value, valueDiags := types.SetValueFrom(ctx, types.Int64Type, ids)
+		diags.Append(valueDiags...)
+		state.ItemIds = value
+	}
+
+	meta, metaDiags := common.MetadataValue(ctx, group.metadata(), managed, preserveUnmanaged.ValueBool())
+	diags.Append(metaDiags...)
+	state.Metadata = meta
+	state.PreserveUnmanagedMetadata = types.BoolValue(preserveUnmanaged.ValueBool())
+
+	state.AccountId = types.Int64Value(group.AccountId)
+
+	return &state, diags
+}
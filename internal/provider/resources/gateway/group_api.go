@@ -0,0 +1,144 @@
+package gateway
+
+import (
+	"fmt"
+	"terraform-provider-flespi/internal/provider/resources/common"
+
+	flespi "github.com/mixser/flespi-client"
+)
+
+// groupObject is a group of gateway entities. The client library has no group
+// support, so groups are managed through the raw gw/groups endpoints. Metadata
+// is a pointer so that a nil value leaves the stored metadata alone while an
+// empty map clears it.
+type groupObject struct {
+	Id        int64              `json:"id,omitempty"`
+	Name      string             `json:"name"`
+	ItemType  string             `json:"item_type,omitempty"`
+	Selector  string             `json:"selector,omitempty"`
+	Metadata  *map[string]string `json:"metadata,omitempty"`
+	AccountId int64              `json:"cid,omitempty"`
+}
+
+// metadata returns the group metadata as read from the API.
+func (g groupObject) metadata() map[string]string {
+	if g.Metadata == nil {
+		return nil
+	}
+
+	return *g.Metadata
+}
+
+type groupsResponse struct {
+	Groups []groupObject `json:"result"`
+}
+
+type groupItemsResponse struct {
+	Items []struct {
+		Id int64 `json:"id"`
+	} `json:"result"`
+}
+
+const groupFields = "id,name,item_type,selector,metadata,cid"
+
+func createGroup(client *flespi.Client, group groupObject) (*groupObject, error) {
+	headers := common.AccountHeaders(group.AccountId)
+	group.AccountId = 0
+
+	response := groupsResponse{}
+
+	if err := client.RequestAPIWithHeaders("POST", "gw/groups", headers, []groupObject{group}, &response); err != nil {
+		return nil, err
+	}
+
+	if len(response.Groups) == 0 {
+		return nil, fmt.Errorf("flespi returned no group")
+	}
+
+	return &response.Groups[0], nil
+}
+
+func getGroup(client *flespi.Client, groupId int64) (*groupObject, error) {
+	response := groupsResponse{}
+
+	endpoint := fmt.Sprintf("gw/groups/%d?fields=%s", groupId, groupFields)
+
+	if err := client.RequestAPI("GET", endpoint, nil, &response); err != nil {
+		return nil, err
+	}
+
+	if len(response.Groups) == 0 {
+		return nil, &flespi.APIError{StatusCode: 404, Method: "GET", Endpoint: endpoint, Message: "group not found"}
+	}
+
+	return &response.Groups[0], nil
+}
+
+// groupUpdate is the body of a group update. Unlike groupObject it always
+// carries the selector, so removing the selector clears it in flespi.
+type groupUpdate struct {
+	Name     string             `json:"name"`
+	Selector string             `json:"selector"`
+	Metadata *map[string]string `json:"metadata,omitempty"`
+}
+
+// updateGroup writes everything but the item type, which can't be changed
+// once the group exists.
+func updateGroup(client *flespi.Client, group groupObject) (*groupObject, error) {
+	headers := common.AccountHeaders(group.AccountId)
+
+	update := groupUpdate{
+		Name:     group.Name,
+		Selector: group.Selector,
+		Metadata: group.Metadata,
+	}
+
+	response := groupsResponse{}
+
+	if err := client.RequestAPIWithHeaders("PUT", fmt.Sprintf("gw/groups/%d", group.Id), headers, update, &response); err != nil {
+		return nil, err
+	}
+
+	if len(response.Groups) == 0 {
+		return nil, fmt.Errorf("flespi returned no group")
+	}
+
+	return &response.Groups[0], nil
+}
+
+func deleteGroup(client *flespi.Client, groupId int64) error {
+	return client.RequestAPI("DELETE", fmt.Sprintf("gw/groups/%d", groupId), nil, nil)
+}
+
+// listGroupItems returns the IDs of the items in the group.
+func listGroupItems(client *flespi.Client, group groupObject) ([]int64, error) {
+	response := groupItemsResponse{}
+
+	if err := client.RequestAPI("GET", fmt.Sprintf("gw/groups/%d/%s/all?fields=id", group.Id, group.ItemType), nil, &response); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, 0, len(response.Items))
+
+	for _, item := range response.Items {
+		ids = append(ids, item.Id)
+	}
+
+	return ids, nil
+}
+
+func addGroupItems(client *flespi.Client, group groupObject, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	return client.RequestAPI("POST", fmt.Sprintf("gw/groups/%d/%s/%s", group.Id, group.ItemType, idSelector(ids)), nil, nil)
+}
+
+func removeGroupItems(client *flespi.Client, group groupObject, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	return client.RequestAPI("DELETE", fmt.Sprintf("gw/groups/%d/%s/%s", group.Id, group.ItemType, idSelector(ids)), nil, nil)
+}
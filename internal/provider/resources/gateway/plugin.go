@@ -0,0 +1,323 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"terraform-provider-flespi/internal/provider/resources/common"
+
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	flespi "github.com/mixser/flespi-client"
+)
+
+var (
+	_ resource.Resource                = &gwPluginResource{}
+	_ resource.ResourceWithConfigure   = &gwPluginResource{}
+	_ resource.ResourceWithImportState = &gwPluginResource{}
+)
+
+type gwPluginResource struct {
+	provider *flespi.Client
+}
+
+type pluginResourceModel struct {
+	Id   types.Int64  `tfsdk:"id"`
+	Name types.String `tfsdk:"name"`
+	Type types.String `tfsdk:"type"`
+
+	ValidateMessage types.String `tfsdk:"validate_message"`
+
+	Configuration jsontypes.Normalized `tfsdk:"configuration"`
+	Metadata      types.Map            `tfsdk:"metadata"`
+	AccountId     types.Int64          `tfsdk:"account_id"`
+
+	PreserveUnmanagedMetadata types.Bool `tfsdk:"preserve_unmanaged_metadata"`
+}
+
+func NewPluginResource() resource.Resource {
+	return &gwPluginResource{}
+}
+
+func (g *gwPluginResource) Metadata(ctx context.Context, request resource.MetadataRequest, response *resource.MetadataResponse) {
+	response.TypeName = request.ProviderTypeName + "_plugin"
+}
+
+func (g *gwPluginResource) Configure(ctx context.Context, request resource.ConfigureRequest, response *resource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	client, ok := request.ProviderData.(*flespi.Client)
+
+	if !ok {
+		response.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *flespi.Client, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+		)
+
+		return
+	}
+
+	g.provider = client
+}
+
+func (g *gwPluginResource) Schema(ctx context.Context, request resource.SchemaRequest, response *resource.SchemaResponse) {
+	response.Schema = schema.Schema{
+		Description: "Device message plugin enriching or filtering messages of the devices it is assigned to.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.Int64Attribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the plugin",
+			},
+			"type": schema.StringAttribute{
+				Required:    true,
+				Description: "Plugin type, e.g. \"msg-pvm-code\". Changing it recreates the plugin.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"validate_message": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Expression a message must match to be processed by the plugin",
+			},
+			"configuration": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				CustomType:  jsontypes.NormalizedType{},
+				Description: "Plugin configuration as JSON, its layout depends on type. Use jsonencode() in HCL.",
+			},
+			"metadata": schema.MapAttribute{
+				Optional:    true,
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Plugin metadata",
+			},
+			"account_id": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Subaccount ID to create the plugin under. Changing it recreates the plugin.",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"preserve_unmanaged_metadata": common.PreserveUnmanagedMetadataAttribute(),
+		},
+	}
+}
+
+func (g *gwPluginResource) Create(ctx context.Context, request resource.CreateRequest, response *resource.CreateResponse) {
+	var data *pluginResourceModel
+
+	response.Diagnostics.Append(request.Plan.Get(ctx, &data)...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	instance := g.convertResourceModelToFlespiPlugin(ctx, *data)
+
+	plugin, err := createPlugin(g.provider, instance)
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Failed to create plugin",
+			fmt.Sprintf("Error creating plugin: %s", err),
+		)
+		return
+	}
+
+	plugin, err = getPlugin(common.ForAccount(g.provider, data.AccountId.ValueInt64()), plugin.Id)
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Failed to get plugin after creation",
+			fmt.Sprintf("Error reading plugin: %s", err),
+		)
+		return
+	}
+
+	result, diags := g.convertFlespiPluginToResourceModel(ctx, plugin, data.Configuration, data.Metadata, data.PreserveUnmanagedMetadata)
+
+	response.Diagnostics.Append(diags...)
+	response.Diagnostics.Append(response.State.Set(ctx, &result)...)
+}
+
+func (g *gwPluginResource) Read(ctx context.Context, request resource.ReadRequest, response *resource.ReadResponse) {
+	var state pluginResourceModel
+
+	diags := request.State.Get(ctx, &state)
+	response.Diagnostics.Append(diags...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	plugin, err := getPlugin(common.ForAccount(g.provider, state.AccountId.ValueInt64()), state.Id.ValueInt64())
+
+	if common.IsNotFound(err) {
+		tflog.Warn(ctx, "Flespi plugin not found, removing from state", map[string]interface{}{"id": state.Id.ValueInt64()})
+		response.State.RemoveResource(ctx)
+		return
+	}
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error Reading Flespi Plugin",
+			"Could not read Flespi plugin ID "+state.Id.String()+": "+err.Error(),
+		)
+
+		return
+	}
+
+	model, diags := g.convertFlespiPluginToResourceModel(ctx, plugin, state.Configuration, state.Metadata, state.PreserveUnmanagedMetadata)
+
+	response.Diagnostics.Append(diags...)
+	response.Diagnostics.Append(response.State.Set(ctx, model)...)
+}
+
+func (g *gwPluginResource) Update(ctx context.Context, request resource.UpdateRequest, response *resource.UpdateResponse) {
+	var plan pluginResourceModel
+	var state pluginResourceModel
+
+	response.Diagnostics.Append(request.Plan.Get(ctx, &plan)...)
+	response.Diagnostics.Append(request.State.Get(ctx, &state)...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Id = state.Id
+
+	var plugin = g.convertResourceModelToFlespiPlugin(ctx, plan)
+
+	plugin.AccountId = state.AccountId.ValueInt64()
+
+	if plan.PreserveUnmanagedMetadata.ValueBool() && plugin.Metadata != nil {
+		current, err := getPlugin(common.ForAccount(g.provider, state.AccountId.ValueInt64()), state.Id.ValueInt64())
+
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Error Reading Flespi Plugin",
+				"Could not read current plugin metadata: "+err.Error(),
+			)
+			return
+		}
+
+		prior, _, diags := common.MetadataElements(ctx, state.Metadata)
+		response.Diagnostics.Append(diags...)
+
+		metadata := common.MetadataToSend(*plugin.Metadata, prior, current.metadata(), true)
+		plugin.Metadata = &metadata
+	}
+
+	_, err := updatePlugin(g.provider, plugin)
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error Updating Flespi Plugin",
+			"Could not update plugin, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	updatedPlugin, err := getPlugin(common.ForAccount(g.provider, state.AccountId.ValueInt64()), state.Id.ValueInt64())
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error Reading Flespi Plugin",
+			"Could not read plugin Id: "+state.Id.String()+": "+err.Error(),
+		)
+		return
+	}
+
+	model, diags := g.convertFlespiPluginToResourceModel(ctx, updatedPlugin, plan.Configuration, common.OwnedMetadata(plan.Metadata, state.Metadata), plan.PreserveUnmanagedMetadata)
+
+	response.Diagnostics.Append(diags...)
+	response.Diagnostics.Append(response.State.Set(ctx, model)...)
+}
+
+func (g *gwPluginResource) Delete(ctx context.Context, request resource.DeleteRequest, response *resource.DeleteResponse) {
+	var state pluginResourceModel
+
+	diags := request.State.Get(ctx, &state)
+
+	response.Diagnostics.Append(diags...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	err := deletePlugin(common.ForAccount(g.provider, state.AccountId.ValueInt64()), state.Id.ValueInt64())
+
+	if err != nil && !common.IsNotFound(err) {
+		response.Diagnostics.AddError(
+			"Error Deleting Flespi Plugin",
+			"Could not delete plugin, unexpected error: "+err.Error(),
+		)
+		return
+	}
+}
+
+func (g *gwPluginResource) ImportState(ctx context.Context, request resource.ImportStateRequest, response *resource.ImportStateResponse) {
+	common.ImportStateByAccountScopedId(ctx, request, response)
+}
+
+func (g *gwPluginResource) convertResourceModelToFlespiPlugin(ctx context.Context, data pluginResourceModel) pluginObject {
+	var metadata *map[string]string
+
+	if elements, ok, _ := common.MetadataElements(ctx, data.Metadata); ok {
+		metadata = &elements
+	}
+
+	var configuration json.RawMessage
+
+	if !data.Configuration.IsNull() && !data.Configuration.IsUnknown() {
+		configuration = json.RawMessage(data.Configuration.ValueString())
+	}
+
+	return pluginObject{
+		Id:              data.Id.ValueInt64(),
+		Name:            data.Name.ValueString(),
+		Type:            data.Type.ValueString(),
+		ValidateMessage: stringSetting(data.ValidateMessage),
+		Configuration:   configuration,
+		Metadata:        metadata,
+		AccountId:       data.AccountId.ValueInt64(),
+	}
+}
+
+func (g *gwPluginResource) convertFlespiPluginToResourceModel(ctx context.Context, plugin *pluginObject, configured jsontypes.Normalized, managed types.Map, preserveUnmanaged types.Bool) (*pluginResourceModel, diag.Diagnostics) {
+	var state pluginResourceModel
+	var diags diag.Diagnostics
+
+	state.Id = types.Int64Value(plugin.Id)
+	state.Name = types.StringValue(plugin.Name)
+	state.Type = types.StringValue(plugin.Type)
+	state.ValidateMessage = types.StringValue(settingValue(plugin.ValidateMessage))
+	state.Configuration = common.PreferConfiguredJSON(configured, rawJSONString(plugin.Configuration))
+
+	meta, metaDiags := common.MetadataValue(ctx, plugin.metadata(), managed, preserveUnmanaged.ValueBool())
+	diags.Append(metaDiags...)
+	state.Metadata = meta
+	state.PreserveUnmanagedMetadata = types.BoolValue(preserveUnmanaged.ValueBool())
+
+	state.AccountId = types.Int64Value(plugin.AccountId)
+
+	return &state, diags
+}
@@ -0,0 +1,100 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"terraform-provider-flespi/internal/provider/resources/common"
+
+	flespi "github.com/mixser/flespi-client"
+)
+
+// pluginObject is a device message plugin. The client library has no plugin
+// support, so plugins are managed through the raw gw/plugins endpoints. The
+// configuration layout depends on the plugin type and is kept as raw JSON.
+// ValidateMessage is a pointer so that a configured empty expression clears it.
+// Metadata is a pointer so that a nil value leaves the stored metadata alone
+// while an empty map clears it.
+type pluginObject struct {
+	Id              int64              `json:"id,omitempty"`
+	Name            string             `json:"name"`
+	Type            string             `json:"type,omitempty"`
+	ValidateMessage *string            `json:"validate_message,omitempty"`
+	Configuration   json.RawMessage    `json:"configuration,omitempty"`
+	Metadata        *map[string]string `json:"metadata,omitempty"`
+	AccountId       int64              `json:"cid,omitempty"`
+}
+
+// metadata returns the plugin metadata as read from the API.
+func (p pluginObject) metadata() map[string]string {
+	if p.Metadata == nil {
+		return nil
+	}
+
+	return *p.Metadata
+}
+
+type pluginsResponse struct {
+	Plugins []pluginObject `json:"result"`
+}
+
+const pluginFields = "id,name,type,validate_message,configuration,metadata,cid"
+
+func createPlugin(client *flespi.Client, plugin pluginObject) (*pluginObject, error) {
+	headers := common.AccountHeaders(plugin.AccountId)
+	plugin.AccountId = 0
+
+	response := pluginsResponse{}
+
+	if err := client.RequestAPIWithHeaders("POST", "gw/plugins", headers, []pluginObject{plugin}, &response); err != nil {
+		return nil, err
+	}
+
+	if len(response.Plugins) == 0 {
+		return nil, fmt.Errorf("flespi returned no plugin")
+	}
+
+	return &response.Plugins[0], nil
+}
+
+func getPlugin(client *flespi.Client, pluginId int64) (*pluginObject, error) {
+	response := pluginsResponse{}
+
+	endpoint := fmt.Sprintf("gw/plugins/%d?fields=%s", pluginId, pluginFields)
+
+	if err := client.RequestAPI("GET", endpoint, nil, &response); err != nil {
+		return nil, err
+	}
+
+	if len(response.Plugins) == 0 {
+		return nil, &flespi.APIError{StatusCode: 404, Method: "GET", Endpoint: endpoint, Message: "plugin not found"}
+	}
+
+	return &response.Plugins[0], nil
+}
+
+// updatePlugin writes everything but the type, which can't be changed once
+// the plugin exists.
+func updatePlugin(client *flespi.Client, plugin pluginObject) (*pluginObject, error) {
+	pluginId := plugin.Id
+	headers := common.AccountHeaders(plugin.AccountId)
+
+	plugin.Id = 0
+	plugin.Type = ""
+	plugin.AccountId = 0
+
+	response := pluginsResponse{}
+
+	if err := client.RequestAPIWithHeaders("PUT", fmt.Sprintf("gw/plugins/%d", pluginId), headers, plugin, &response); err != nil {
+		return nil, err
+	}
+
+	if len(response.Plugins) == 0 {
+		return nil, fmt.Errorf("flespi returned no plugin")
+	}
+
+	return &response.Plugins[0], nil
+}
+
+func deletePlugin(client *flespi.Client, pluginId int64) error {
+	return client.RequestAPI("DELETE", fmt.Sprintf("gw/plugins/%d", pluginId), nil, nil)
+}
@@ -0,0 +1,35 @@
+package gateway
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+)
+
+func NewPluginDeviceAssignmentResource() resource.Resource {
+	return &gwAssignmentResource{
+		kind: assignmentKind{
+			typeName:        "plugin_device_assignment",
+			description:     "Assigns a flespi plugin to a device, so the plugin processes the device messages.",
+			parentEndpoint:  "plugins",
+			parentAttribute: "plugin_id",
+			parentTitle:     "plugin",
+			childEndpoint:   "devices",
+			childAttribute:  "device_id",
+			childTitle:      "device",
+		},
+	}
+}
+
+func NewPluginGroupAssignmentResource() resource.Resource {
+	return &gwAssignmentResource{
+		kind: assignmentKind{
+			typeName:        "plugin_group_assignment",
+			description:     "Assigns a flespi plugin to a device group, so the plugin processes the messages of every device in the group.",
+			parentEndpoint:  "plugins",
+			parentAttribute: "plugin_id",
+			parentTitle:     "plugin",
+			childEndpoint:   "groups",
+			childAttribute:  "group_id",
+			childTitle:      "group",
+		},
+	}
+}
@@ -0,0 +1,331 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"terraform-provider-flespi/internal/provider/resources/common"
+
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	flespi "github.com/mixser/flespi-client"
+)
+
+// The flespi client library has no bindings for the protocol catalog, so these
+// helpers call the REST API directly through the provider client.
+
+type channelProtocol struct {
+	Id     int64           `json:"id"`
+	Name   string          `json:"name"`
+	Title  string          `json:"title"`
+	Schema json.RawMessage `json:"schema"`
+}
+
+type streamProtocol struct {
+	Id     int64           `json:"id"`
+	Name   string          `json:"name"`
+	Title  string          `json:"title"`
+	Schema json.RawMessage `json:"schema"`
+}
+
+type deviceType struct {
+	Id         int64           `json:"id"`
+	Name       string          `json:"name"`
+	Title      string          `json:"title"`
+	ProtocolId int64           `json:"protocol_id"`
+	Schema     json.RawMessage `json:"schema"`
+}
+
+func listChannelProtocols(client *flespi.Client) ([]channelProtocol, error) {
+	var response struct {
+		Result []channelProtocol `json:"result"`
+	}
+
+	if err := client.RequestAPI("GET", "gw/channel-protocols/all", nil, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Result, nil
+}
+
+func getChannelProtocol(client *flespi.Client, protocolId int64) (*channelProtocol, error) {
+	var response struct {
+		Result []channelProtocol `json:"result"`
+	}
+
+	if err := client.RequestAPI("GET", fmt.Sprintf("gw/channel-protocols/%d", protocolId), nil, &response); err != nil {
+		return nil, err
+	}
+
+	if len(response.Result) == 0 {
+		return nil, fmt.Errorf("channel protocol %d not found", protocolId)
+	}
+
+	return &response.Result[0], nil
+}
+
+func listStreamProtocols(client *flespi.Client) ([]streamProtocol, error) {
+	var response struct {
+		Result []streamProtocol `json:"result"`
+	}
+
+	if err := client.RequestAPI("GET", "gw/stream-protocols/all", nil, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Result, nil
+}
+
+func getStreamProtocol(client *flespi.Client, protocolId int64) (*streamProtocol, error) {
+	var response struct {
+		Result []streamProtocol `json:"result"`
+	}
+
+	if err := client.RequestAPI("GET", fmt.Sprintf("gw/stream-protocols/%d", protocolId), nil, &response); err != nil {
+		return nil, err
+	}
+
+	if len(response.Result) == 0 {
+		return nil, fmt.Errorf("stream protocol %d not found", protocolId)
+	}
+
+	return &response.Result[0], nil
+}
+
+func listDeviceTypes(client *flespi.Client, protocolId int64) ([]deviceType, error) {
+	var response struct {
+		Result []deviceType `json:"result"`
+	}
+
+	if err := client.RequestAPI("GET", fmt.Sprintf("gw/channel-protocols/%d/device-types/all", protocolId), nil, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Result, nil
+}
+
+// findChannelProtocol looks a protocol up by its name, ignoring case as flespi
+// protocol names are lowercase while the web UI shows them capitalized.
+func findChannelProtocol(protocols []channelProtocol, name string) (*channelProtocol, error) {
+	for _, protocol := range protocols {
+		if strings.EqualFold(protocol.Name, name) {
+			return &protocol, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no channel protocol named %q found", name)
+}
+
+func findStreamProtocol(protocols []streamProtocol, name string) (*streamProtocol, error) {
+	for _, protocol := range protocols {
+		if strings.EqualFold(protocol.Name, name) {
+			return &protocol, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no stream protocol named %q found", name)
+}
+
+// findDeviceType matches model against the device type name (e.g.
+// "teltonika-fmb920") first and then against its title (e.g. "FMB920").
+func findDeviceType(deviceTypes []deviceType, model string) (*deviceType, error) {
+	for _, match := range []func(deviceType) string{
+		func(item deviceType) string { return item.Name },
+		func(item deviceType) string { return item.Title },
+	} {
+		var found []deviceType
+
+		for _, item := range deviceTypes {
+			if strings.EqualFold(match(item), model) {
+				found = append(found, item)
+			}
+		}
+
+		switch len(found) {
+		case 0:
+			continue
+		case 1:
+			return &found[0], nil
+		default:
+			ids := make([]string, 0, len(found))
+			for _, item := range found {
+				ids = append(ids, fmt.Sprintf("%d", item.Id))
+			}
+
+			return nil, fmt.Errorf("%d device types match %q (ids: %s)", len(found), model, strings.Join(ids, ", "))
+		}
+	}
+
+	return nil, fmt.Errorf("no device type named %q found", model)
+}
+
+// protocolCatalog caches protocol schemas for the lifetime of a provider run,
+// so validating many resources during one plan fetches every schema once.
+type protocolCatalog struct {
+	client *flespi.Client
+
+	mu               sync.Mutex
+	channelProtocols []channelProtocol
+	channelSchemas   map[int64]json.RawMessage
+	streamSchemas    map[int64]json.RawMessage
+}
+
+var catalogs sync.Map
+
+// catalogFor returns the catalog shared by everything configured with client.
+func catalogFor(client *flespi.Client) *protocolCatalog {
+	catalog, _ := catalogs.LoadOrStore(client, &protocolCatalog{client: client})
+
+	return catalog.(*protocolCatalog)
+}
+
+// channelProtocolId resolves a channel protocol name to its ID.
+func (c *protocolCatalog) channelProtocolId(name string) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.channelProtocols == nil {
+		protocols, err := listChannelProtocols(c.client)
+
+		if err != nil {
+			return 0, err
+		}
+
+		c.channelProtocols = protocols
+	}
+
+	protocol, err := findChannelProtocol(c.channelProtocols, name)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return protocol.Id, nil
+}
+
+// channelSchema returns the configuration schema of a channel protocol.
+func (c *protocolCatalog) channelSchema(protocolId int64) (json.RawMessage, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if schema, ok := c.channelSchemas[protocolId]; ok {
+		return schema, nil
+	}
+
+	protocol, err := getChannelProtocol(c.client, protocolId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if c.channelSchemas == nil {
+		c.channelSchemas = make(map[int64]json.RawMessage)
+	}
+
+	c.channelSchemas[protocolId] = protocol.Schema
+
+	return protocol.Schema, nil
+}
+
+// streamSchema returns the configuration schema of a stream protocol.
+func (c *protocolCatalog) streamSchema(protocolId int64) (json.RawMessage, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if schema, ok := c.streamSchemas[protocolId]; ok {
+		return schema, nil
+	}
+
+	protocol, err := getStreamProtocol(c.client, protocolId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if c.streamSchemas == nil {
+		c.streamSchemas = make(map[int64]json.RawMessage)
+	}
+
+	c.streamSchemas[protocolId] = protocol.Schema
+
+	return protocol.Schema, nil
+}
+
+// validateConfiguration reports every place where configuration does not match
+// schema as an error on the configuration attribute.
+func validateConfiguration(schema json.RawMessage, configuration jsontypes.Normalized, subject string, diags *diag.Diagnostics) {
+	var value interface{}
+
+	// Invalid JSON is already reported by the attribute type itself.
+	if err := json.Unmarshal([]byte(configuration.ValueString()), &value); err != nil {
+		return
+	}
+
+	problems, err := common.ValidateJSONSchema(schema, value)
+
+	if err != nil {
+		diags.AddAttributeWarning(
+			path.Root("configuration"),
+			"Unable to Validate Configuration",
+			fmt.Sprintf("The configuration schema of %s could not be used: %s", subject, err),
+		)
+		return
+	}
+
+	for _, problem := range problems {
+		diags.AddAttributeError(
+			path.Root("configuration"),
+			"Invalid Configuration",
+			fmt.Sprintf("Configuration does not match the schema of %s: %s", subject, problem),
+		)
+	}
+}
+
+// rawJSONString renders a JSON object returned by the API, such as a
+// configuration or its schema, for a jsontypes.Normalized attribute. A missing
+// object becomes an empty one.
+func rawJSONString(value json.RawMessage) string {
+	if len(value) == 0 || string(value) == "null" {
+		return "{}"
+	}
+
+	return string(value)
+}
+
+// rawJSONArrayString is rawJSONString for lists such as calculator selectors.
+// A missing list becomes an empty one.
+func rawJSONArrayString(value json.RawMessage) string {
+	if len(value) == 0 || string(value) == "null" {
+		return "[]"
+	}
+
+	return string(value)
+}
+
+// mapToNormalizedJSON converts a map of strings, the form configurations used
+// to be stored in, to a JSON object for state upgrades.
+func mapToNormalizedJSON(ctx context.Context, value types.Map) (jsontypes.Normalized, diag.Diagnostics) {
+	if value.IsNull() || value.IsUnknown() {
+		return jsontypes.NewNormalizedNull(), nil
+	}
+
+	elements := map[string]string{}
+
+	if diags := value.ElementsAs(ctx, &elements, false); diags.HasError() {
+		return jsontypes.NewNormalizedNull(), diags
+	}
+
+	encoded, err := json.Marshal(elements)
+
+	if err != nil {
+		return jsontypes.NewNormalizedNull(), diag.Diagnostics{
+			diag.NewErrorDiagnostic("Unable to Upgrade Configuration", err.Error()),
+		}
+	}
+
+	return jsontypes.NewNormalizedValue(string(encoded)), nil
+}
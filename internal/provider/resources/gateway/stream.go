@@ -2,27 +2,35 @@ package gateway
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"terraform-provider-flespi/internal/provider/resources/common"
 
-	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	flespi "github.com/mixser/flespi-client"
 	flespi_stream "github.com/mixser/flespi-client/resources/gateway/stream"
 )
 
 var (
-	_ resource.Resource              = &gwStreamResource{}
-	_ resource.ResourceWithConfigure = &gwStreamResource{}
+	_ resource.Resource                 = &gwStreamResource{}
+	_ resource.ResourceWithConfigure    = &gwStreamResource{}
+	_ resource.ResourceWithImportState  = &gwStreamResource{}
+	_ resource.ResourceWithModifyPlan   = &gwStreamResource{}
+	_ resource.ResourceWithUpgradeState = &gwStreamResource{}
 )
 
 type gwStreamResource struct {
-	client *flespi_stream.StreamClient
+	client   *flespi_stream.StreamClient
+	provider *flespi.Client
 }
 
 type streamResourceModel struct {
@@ -35,6 +43,24 @@ type streamResourceModel struct {
 
 	ValidateMessage types.String `tfsdk:"validate_message"`
 
+	Configuration jsontypes.Normalized `tfsdk:"configuration"`
+	Metadata      types.Map            `tfsdk:"metadata"`
+	AccountId     types.Int64          `tfsdk:"account_id"`
+
+	PreserveUnmanagedMetadata types.Bool `tfsdk:"preserve_unmanaged_metadata"`
+}
+
+// streamResourceModelV0 is the state layout before configuration became JSON.
+type streamResourceModelV0 struct {
+	Id         types.Int64  `tfsdk:"id"`
+	Name       types.String `tfsdk:"name"`
+	ProtocolId types.Int64  `tfsdk:"protocol_id"`
+
+	Enabled  types.Bool  `tfsdk:"enabled"`
+	QueueTTL types.Int64 `tfsdk:"queue_ttl"`
+
+	ValidateMessage types.String `tfsdk:"validate_message"`
+
 	Configuration types.Map   `tfsdk:"configuration"`
 	Metadata      types.Map   `tfsdk:"metadata"`
 	AccountId     types.Int64 `tfsdk:"account_id"`
@@ -65,10 +91,17 @@ func (g *gwStreamResource) Configure(ctx context.Context, request resource.Confi
 	}
 
 	g.client = client.Streams
+	g.provider = client
+}
+
+// clientFor returns a stream client acting as the (sub)account that owns the stream.
+func (g *gwStreamResource) clientFor(accountId types.Int64) *flespi_stream.StreamClient {
+	return common.ForAccount(g.provider, accountId.ValueInt64()).Streams
 }
 
 func (g *gwStreamResource) Schema(ctx context.Context, request resource.SchemaRequest, response *resource.SchemaResponse) {
 	response.Schema = schema.Schema{
+		Version: 1,
 		Attributes: map[string]schema.Attribute{
 			"id": schema.Int64Attribute{
 				Computed: true,
@@ -82,7 +115,7 @@ func (g *gwStreamResource) Schema(ctx context.Context, request resource.SchemaRe
 			},
 			"protocol_id": schema.Int64Attribute{
 				Required:    true,
-				Description: "Protocol ID for the stream",
+				Description: "Protocol ID for the stream. Look it up with the flespi_stream_protocol data source.",
 			},
 			"enabled": schema.BoolAttribute{
 				Required:    true,
@@ -99,11 +132,11 @@ func (g *gwStreamResource) Schema(ctx context.Context, request resource.SchemaRe
 				Computed:    true,
 				Description: "Message validation expression",
 			},
-			"configuration": schema.MapAttribute{
+			"configuration": schema.StringAttribute{
 				Optional:    true,
 				Computed:    true,
-				ElementType: types.StringType,
-				Description: "Stream configuration",
+				CustomType:  jsontypes.NormalizedType{},
+				Description: "Stream configuration as JSON, validated against the configuration_schema of the flespi_stream_protocol data source. Use jsonencode() in HCL.",
 			},
 			"metadata": schema.MapAttribute{
 				Optional:    true,
@@ -116,6 +149,7 @@ func (g *gwStreamResource) Schema(ctx context.Context, request resource.SchemaRe
 				Computed:    true,
 				Description: "Subaccount ID to create the stream under.",
 			},
+			"preserve_unmanaged_metadata": common.PreserveUnmanagedMetadataAttribute(),
 		},
 	}
 }
@@ -131,20 +165,7 @@ func (g *gwStreamResource) Create(ctx context.Context, request resource.CreateRe
 
 	instance := g.convertResourceModelToFlespiStream(ctx, *data)
 
-	createOpts := []flespi_stream.CreateStreamOption{
-		flespi_stream.WithStatus(instance.Enabled),
-		flespi_stream.WithQueueTTL(instance.QueueTTL),
-		flespi_stream.WithValidateMessage(instance.ValidateMessage),
-		flespi_stream.WithConfiguration(instance.Configuration),
-		flespi_stream.WithMetadata(instance.Metadata),
-		flespi_stream.WithAccountId(instance.AccountId),
-	}
-
-	streamInstance, err := g.client.Create(
-		instance.Name,
-		instance.ProtocolId,
-		createOpts...,
-	)
+	streamInstance, err := createStream(g.provider, instance)
 
 	if err != nil {
 		response.Diagnostics.AddError(
@@ -154,7 +175,7 @@ func (g *gwStreamResource) Create(ctx context.Context, request resource.CreateRe
 		return
 	}
 
-	streamInstance, err = g.client.Get(streamInstance.Id)
+	streamInstance, err = getStream(common.ForAccount(g.provider, data.AccountId.ValueInt64()), streamInstance.Id)
 
 	if err != nil {
 		response.Diagnostics.AddError(
@@ -164,7 +185,7 @@ func (g *gwStreamResource) Create(ctx context.Context, request resource.CreateRe
 		return
 	}
 
-	result, diags := g.convertFlespiStreamToResourceModel(streamInstance)
+	result, diags := g.convertFlespiStreamToResourceModel(ctx, streamInstance, data.Configuration, data.Metadata, data.PreserveUnmanagedMetadata)
 
 	response.Diagnostics.Append(diags...)
 	response.Diagnostics.Append(response.State.Set(ctx, &result)...)
@@ -180,7 +201,13 @@ func (g *gwStreamResource) Read(ctx context.Context, request resource.ReadReques
 		return
 	}
 
-	stream, err := g.client.Get(state.Id.ValueInt64())
+	stream, err := getStream(common.ForAccount(g.provider, state.AccountId.ValueInt64()), state.Id.ValueInt64())
+
+	if common.IsNotFound(err) {
+		tflog.Warn(ctx, "Flespi stream not found, removing from state", map[string]interface{}{"id": state.Id.ValueInt64()})
+		response.State.RemoveResource(ctx)
+		return
+	}
 
 	if err != nil {
 		response.Diagnostics.AddError(
@@ -191,7 +218,7 @@ func (g *gwStreamResource) Read(ctx context.Context, request resource.ReadReques
 		return
 	}
 
-	model, diags := g.convertFlespiStreamToResourceModel(stream)
+	model, diags := g.convertFlespiStreamToResourceModel(ctx, stream, state.Configuration, state.Metadata, state.PreserveUnmanagedMetadata)
 
 	response.Diagnostics.Append(diags...)
 	response.Diagnostics.Append(response.State.Set(ctx, model)...)
@@ -212,7 +239,27 @@ func (g *gwStreamResource) Update(ctx context.Context, request resource.UpdateRe
 
 	var stream = g.convertResourceModelToFlespiStream(ctx, plan)
 
-	_, err := g.client.Update(stream)
+	stream.AccountId = state.AccountId.ValueInt64()
+
+	if plan.PreserveUnmanagedMetadata.ValueBool() && stream.Metadata != nil {
+		current, err := getStream(common.ForAccount(g.provider, state.AccountId.ValueInt64()), state.Id.ValueInt64())
+
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Error Reading Flespi Stream",
+				"Could not read current stream metadata: "+err.Error(),
+			)
+			return
+		}
+
+		prior, _, diags := common.MetadataElements(ctx, state.Metadata)
+		response.Diagnostics.Append(diags...)
+
+		metadata := common.MetadataToSend(*stream.Metadata, prior, current.metadata(), true)
+		stream.Metadata = &metadata
+	}
+
+	_, err := updateStream(g.provider, stream)
 
 	if err != nil {
 		response.Diagnostics.AddError(
@@ -222,16 +269,17 @@ func (g *gwStreamResource) Update(ctx context.Context, request resource.UpdateRe
 		return
 	}
 
-	updatedStream, err := g.client.Get(state.Id.ValueInt64())
+	updatedStream, err := getStream(common.ForAccount(g.provider, state.AccountId.ValueInt64()), state.Id.ValueInt64())
 
 	if err != nil {
 		response.Diagnostics.AddError(
 			"Error Reading Flespi Stream",
 			"Could not read stream Id: "+state.Id.String()+": "+err.Error(),
 		)
+		return
 	}
 
-	model, diags := g.convertFlespiStreamToResourceModel(updatedStream)
+	model, diags := g.convertFlespiStreamToResourceModel(ctx, updatedStream, plan.Configuration, common.OwnedMetadata(plan.Metadata, state.Metadata), plan.PreserveUnmanagedMetadata)
 
 	response.Diagnostics.Append(diags...)
 	response.Diagnostics.Append(response.State.Set(ctx, model)...)
@@ -248,9 +296,9 @@ func (g *gwStreamResource) Delete(ctx context.Context, request resource.DeleteRe
 		return
 	}
 
-	err := g.client.DeleteById(state.Id.ValueInt64())
+	err := g.clientFor(state.AccountId).DeleteById(state.Id.ValueInt64())
 
-	if err != nil {
+	if err != nil && !common.IsNotFound(err) {
 		response.Diagnostics.AddError(
 			"Error Deleting Flespi Stream",
 			"Could not delete stream, unexpected error: "+err.Error(),
@@ -259,19 +307,105 @@ func (g *gwStreamResource) Delete(ctx context.Context, request resource.DeleteRe
 	}
 }
 
-func (g *gwStreamResource) convertResourceModelToFlespiStream(ctx context.Context, data streamResourceModel) flespi_stream.Stream {
-	configuration := make(map[string]string)
-	metadata := make(map[string]string)
+func (g *gwStreamResource) ImportState(ctx context.Context, request resource.ImportStateRequest, response *resource.ImportStateResponse) {
+	common.ImportStateByAccountScopedId(ctx, request, response)
+}
+
+// ModifyPlan checks configuration against the configuration schema of the
+// stream protocol, so invalid settings are reported during plan.
+func (g *gwStreamResource) ModifyPlan(ctx context.Context, request resource.ModifyPlanRequest, response *resource.ModifyPlanResponse) {
+	if request.Plan.Raw.IsNull() || g.provider == nil {
+		return
+	}
+
+	var plan streamResourceModel
+
+	response.Diagnostics.Append(request.Plan.Get(ctx, &plan)...)
+
+	if response.Diagnostics.HasError() || plan.Configuration.IsNull() || plan.Configuration.IsUnknown() || plan.ProtocolId.IsUnknown() {
+		return
+	}
+
+	configSchema, err := catalogFor(g.provider).streamSchema(plan.ProtocolId.ValueInt64())
+
+	if err != nil {
+		response.Diagnostics.AddAttributeWarning(
+			path.Root("configuration"),
+			"Unable to Validate Stream Configuration",
+			fmt.Sprintf("Could not fetch the configuration schema of stream protocol %d: %s", plan.ProtocolId.ValueInt64(), err),
+		)
+		return
+	}
+
+	validateConfiguration(configSchema, plan.Configuration, fmt.Sprintf("stream protocol %d", plan.ProtocolId.ValueInt64()), &response.Diagnostics)
+}
+
+func (g *gwStreamResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		// Version 0 stored configuration as a map of strings.
+		0: {
+			PriorSchema: &schema.Schema{
+				Attributes: map[string]schema.Attribute{
+					"id":               schema.Int64Attribute{Computed: true},
+					"name":             schema.StringAttribute{Required: true},
+					"protocol_id":      schema.Int64Attribute{Required: true},
+					"enabled":          schema.BoolAttribute{Required: true},
+					"queue_ttl":        schema.Int64Attribute{Optional: true, Computed: true},
+					"validate_message": schema.StringAttribute{Optional: true, Computed: true},
+					"configuration":    schema.MapAttribute{Optional: true, Computed: true, ElementType: types.StringType},
+					"metadata":         schema.MapAttribute{Optional: true, Computed: true, ElementType: types.StringType},
+					"account_id":       schema.Int64Attribute{Optional: true, Computed: true},
+				},
+			},
+			StateUpgrader: func(ctx context.Context, request resource.UpgradeStateRequest, response *resource.UpgradeStateResponse) {
+				var prior streamResourceModelV0
+
+				response.Diagnostics.Append(request.State.Get(ctx, &prior)...)
+
+				if response.Diagnostics.HasError() {
+					return
+				}
+
+				configuration, diags := mapToNormalizedJSON(ctx, prior.Configuration)
+
+				response.Diagnostics.Append(diags...)
+
+				if response.Diagnostics.HasError() {
+					return
+				}
+
+				response.Diagnostics.Append(response.State.Set(ctx, streamResourceModel{
+					Id:              prior.Id,
+					Name:            prior.Name,
+					ProtocolId:      prior.ProtocolId,
+					Enabled:         prior.Enabled,
+					QueueTTL:        prior.QueueTTL,
+					ValidateMessage: prior.ValidateMessage,
+					Configuration:   configuration,
+					Metadata:        prior.Metadata,
+					AccountId:       prior.AccountId,
+
+					PreserveUnmanagedMetadata: types.BoolValue(false),
+				})...)
+			},
+		},
+	}
+}
+
+func (g *gwStreamResource) convertResourceModelToFlespiStream(ctx context.Context, data streamResourceModel) streamObject {
+	var metadata *map[string]string
 
-	if !data.Configuration.IsNull() {
-		data.Configuration.ElementsAs(ctx, &configuration, false)
+	if elements, ok, _ := common.MetadataElements(ctx, data.Metadata); ok {
+		metadata = &elements
 	}
 
-	if !data.Metadata.IsNull() {
-		data.Metadata.ElementsAs(ctx, &metadata, false)
+	var configuration json.RawMessage
+
+	if !data.Configuration.IsNull() && !data.Configuration.IsUnknown() {
+		configuration = json.RawMessage(data.Configuration.ValueString())
 	}
 
-	return flespi_stream.Stream{
+	return streamObject{
 		Id:              data.Id.ValueInt64(),
 		Name:            data.Name.ValueString(),
 		ProtocolId:      data.ProtocolId.ValueInt64(),
@@ -284,8 +418,9 @@ func (g *gwStreamResource) convertResourceModelToFlespiStream(ctx context.Contex
 	}
 }
 
-func (g *gwStreamResource) convertFlespiStreamToResourceModel(stream *flespi_stream.Stream) (*streamResourceModel, diag.Diagnostics) {
+func (g *gwStreamResource) convertFlespiStreamToResourceModel(ctx context.Context, stream *streamObject, configured jsontypes.Normalized, managed types.Map, preserveUnmanaged types.Bool) (*streamResourceModel, diag.Diagnostics) {
 	var state streamResourceModel
+	var diags diag.Diagnostics
 
 	state.Id = types.Int64Value(stream.Id)
 	state.Name = types.StringValue(stream.Name)
@@ -293,23 +428,12 @@ func (g *gwStreamResource) convertFlespiStreamToResourceModel(stream *flespi_str
 	state.Enabled = types.BoolValue(stream.Enabled)
 	state.QueueTTL = types.Int64Value(stream.QueueTTL)
 	state.ValidateMessage = types.StringValue(stream.ValidateMessage)
+	state.Configuration = common.PreferConfiguredJSON(configured, rawJSONString(stream.Configuration))
 
-	configuration := make(map[string]attr.Value)
-	for key, value := range stream.Configuration {
-		configuration[key] = types.StringValue(value)
-	}
-
-	cfg, diags := types.MapValue(types.StringType, configuration)
-	state.Configuration = cfg
-
-	metadata := make(map[string]attr.Value)
-	for key, value := range stream.Metadata {
-		metadata[key] = types.StringValue(value)
-	}
-
-	meta, metaDiags := types.MapValue(types.StringType, metadata)
+	meta, metaDiags := common.MetadataValue(ctx, stream.metadata(), managed, preserveUnmanaged.ValueBool())
 	diags.Append(metaDiags...)
 	state.Metadata = meta
+	state.PreserveUnmanagedMetadata = types.BoolValue(preserveUnmanaged.ValueBool())
 
 	state.AccountId = types.Int64Value(stream.AccountId)
 
@@ -0,0 +1,104 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"terraform-provider-flespi/internal/provider/resources/common"
+
+	flespi "github.com/mixser/flespi-client"
+)
+
+// streamObject mirrors flespi_stream.Stream but keeps the configuration as raw
+// JSON: stream configurations nest objects (headers, topics, auth) and use
+// numbers and booleans, which the client library's map of strings can't hold.
+// Metadata is a pointer so that a nil value leaves the stored metadata alone
+// while an empty map clears it.
+type streamObject struct {
+	Id              int64              `json:"id,omitempty"`
+	Name            string             `json:"name"`
+	ProtocolId      int64              `json:"protocol_id"`
+	Enabled         bool               `json:"enabled"`
+	QueueTTL        int64              `json:"queue_ttl,omitempty"`
+	ValidateMessage string             `json:"validate_message,omitempty"`
+	Configuration   json.RawMessage    `json:"configuration,omitempty"`
+	Metadata        *map[string]string `json:"metadata,omitempty"`
+	AccountId       int64              `json:"cid,omitempty"`
+}
+
+// metadata returns the stream metadata as read from the API.
+func (s streamObject) metadata() map[string]string {
+	if s.Metadata == nil {
+		return nil
+	}
+
+	return *s.Metadata
+}
+
+type streamsResponse struct {
+	Streams []streamObject `json:"result"`
+}
+
+const streamFields = "id,name,protocol_id,enabled,queue_ttl,validate_message,configuration,metadata,cid"
+
+func createStream(client *flespi.Client, stream streamObject) (*streamObject, error) {
+	headers := common.AccountHeaders(stream.AccountId)
+	stream.AccountId = 0
+
+	response := streamsResponse{}
+
+	if err := client.RequestAPIWithHeaders("POST", "gw/streams", headers, []streamObject{stream}, &response); err != nil {
+		return nil, err
+	}
+
+	if len(response.Streams) == 0 {
+		return nil, fmt.Errorf("flespi returned no stream")
+	}
+
+	return &response.Streams[0], nil
+}
+
+func getStream(client *flespi.Client, streamId int64) (*streamObject, error) {
+	response := streamsResponse{}
+
+	endpoint := fmt.Sprintf("gw/streams/%d?fields=%s", streamId, streamFields)
+
+	if err := client.RequestAPI("GET", endpoint, nil, &response); err != nil {
+		return nil, err
+	}
+
+	if len(response.Streams) == 0 {
+		return nil, &flespi.APIError{StatusCode: 404, Method: "GET", Endpoint: endpoint, Message: "stream not found"}
+	}
+
+	return &response.Streams[0], nil
+}
+
+func listStreams(client *flespi.Client) ([]streamObject, error) {
+	response := streamsResponse{}
+
+	if err := client.RequestAPI("GET", "gw/streams/all?fields="+streamFields, nil, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Streams, nil
+}
+
+func updateStream(client *flespi.Client, stream streamObject) (*streamObject, error) {
+	streamId := stream.Id
+	headers := common.AccountHeaders(stream.AccountId)
+
+	stream.Id = 0
+	stream.AccountId = 0
+
+	response := streamsResponse{}
+
+	if err := client.RequestAPIWithHeaders("PUT", fmt.Sprintf("gw/streams/%d", streamId), headers, stream, &response); err != nil {
+		return nil, err
+	}
+
+	if len(response.Streams) == 0 {
+		return nil, fmt.Errorf("flespi returned no stream")
+	}
+
+	return &response.Streams[0], nil
+}
@@ -0,0 +1,321 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-flespi/internal/provider/resources/common"
+
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	flespi "github.com/mixser/flespi-client"
+)
+
+var (
+	_ datasource.DataSource                     = &gwStreamDataSource{}
+	_ datasource.DataSourceWithConfigure        = &gwStreamDataSource{}
+	_ datasource.DataSourceWithConfigValidators = &gwStreamDataSource{}
+	_ datasource.DataSource                     = &gwStreamsDataSource{}
+	_ datasource.DataSourceWithConfigure        = &gwStreamsDataSource{}
+)
+
+type gwStreamDataSource struct {
+	client *flespi.Client
+}
+
+type gwStreamsDataSource struct {
+	client *flespi.Client
+}
+
+type streamDataSourceModel struct {
+	Id              types.Int64          `tfsdk:"id"`
+	Name            types.String         `tfsdk:"name"`
+	ProtocolId      types.Int64          `tfsdk:"protocol_id"`
+	Enabled         types.Bool           `tfsdk:"enabled"`
+	QueueTTL        types.Int64          `tfsdk:"queue_ttl"`
+	ValidateMessage types.String         `tfsdk:"validate_message"`
+	Configuration   jsontypes.Normalized `tfsdk:"configuration"`
+	Metadata        types.Map            `tfsdk:"metadata"`
+	AccountId       types.Int64          `tfsdk:"account_id"`
+}
+
+type streamsDataSourceModel struct {
+	NameRegex     types.String            `tfsdk:"name_regex"`
+	AccountId     types.Int64             `tfsdk:"account_id"`
+	Enabled       types.Bool              `tfsdk:"enabled"`
+	MetadataKey   types.String            `tfsdk:"metadata_key"`
+	MetadataValue types.String            `tfsdk:"metadata_value"`
+	Streams       []streamDataSourceModel `tfsdk:"streams"`
+}
+
+func NewStreamDataSource() datasource.DataSource {
+	return &gwStreamDataSource{}
+}
+
+func NewStreamsDataSource() datasource.DataSource {
+	return &gwStreamsDataSource{}
+}
+
+func streamDataSourceAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"id": schema.Int64Attribute{
+			Computed: true,
+		},
+		"name": schema.StringAttribute{
+			Computed: true,
+		},
+		"protocol_id": schema.Int64Attribute{
+			Computed: true,
+		},
+		"enabled": schema.BoolAttribute{
+			Computed: true,
+		},
+		"queue_ttl": schema.Int64Attribute{
+			Computed: true,
+		},
+		"validate_message": schema.StringAttribute{
+			Computed: true,
+		},
+		"configuration": schema.StringAttribute{
+			Computed:   true,
+			CustomType: jsontypes.NormalizedType{},
+		},
+		"metadata": schema.MapAttribute{
+			Computed:    true,
+			ElementType: types.StringType,
+		},
+		"account_id": schema.Int64Attribute{
+			Computed: true,
+		},
+	}
+}
+
+func (d *gwStreamDataSource) Metadata(ctx context.Context, request datasource.MetadataRequest, response *datasource.MetadataResponse) {
+	response.TypeName = request.ProviderTypeName + "_stream"
+}
+
+func (d *gwStreamDataSource) Configure(ctx context.Context, request datasource.ConfigureRequest, response *datasource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	client, ok := request.ProviderData.(*flespi.Client)
+
+	if !ok {
+		response.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *flespi.Client, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *gwStreamDataSource) Schema(ctx context.Context, request datasource.SchemaRequest, response *datasource.SchemaResponse) {
+	attributes := streamDataSourceAttributes()
+
+	attributes["id"] = schema.Int64Attribute{
+		Optional:    true,
+		Computed:    true,
+		Description: "ID of the stream to look up.",
+	}
+	attributes["name"] = schema.StringAttribute{
+		Optional:    true,
+		Computed:    true,
+		Description: "Exact name of the stream to look up.",
+	}
+
+	attributes["account_id"] = schema.Int64Attribute{
+		Optional:    true,
+		Computed:    true,
+		Description: "(Sub)account to look the stream up in. Defaults to the provider's account_id.",
+	}
+
+	response.Schema = schema.Schema{
+		Description: "Looks up a single flespi stream by id or exact name.",
+		Attributes:  attributes,
+	}
+}
+
+func (d *gwStreamDataSource) ConfigValidators(ctx context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		datasourcevalidator.ExactlyOneOf(
+			path.MatchRoot("id"),
+			path.MatchRoot("name"),
+		),
+	}
+}
+
+func (d *gwStreamDataSource) Read(ctx context.Context, request datasource.ReadRequest, response *datasource.ReadResponse) {
+	var config streamDataSourceModel
+
+	response.Diagnostics.Append(request.Config.Get(ctx, &config)...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	client := common.ForAccount(d.client, config.AccountId.ValueInt64())
+
+	var stream *streamObject
+	var err error
+
+	if !config.Id.IsNull() {
+		stream, err = getStream(client, config.Id.ValueInt64())
+	} else {
+		var streams []streamObject
+
+		streams, err = listStreams(client)
+
+		if err == nil {
+			stream, err = common.FindByName(streams, config.Name.ValueString(),
+				func(item streamObject) string { return item.Name },
+				func(item streamObject) int64 { return item.Id },
+			)
+		}
+	}
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error Reading Flespi Stream",
+			"Could not look up Flespi stream: "+err.Error(),
+		)
+		return
+	}
+
+	model, diags := convertFlespiStreamToDataSourceModel(ctx, stream)
+
+	response.Diagnostics.Append(diags...)
+	response.Diagnostics.Append(response.State.Set(ctx, model)...)
+}
+
+func (d *gwStreamsDataSource) Metadata(ctx context.Context, request datasource.MetadataRequest, response *datasource.MetadataResponse) {
+	response.TypeName = request.ProviderTypeName + "_streams"
+}
+
+func (d *gwStreamsDataSource) Configure(ctx context.Context, request datasource.ConfigureRequest, response *datasource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	client, ok := request.ProviderData.(*flespi.Client)
+
+	if !ok {
+		response.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *flespi.Client, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *gwStreamsDataSource) Schema(ctx context.Context, request datasource.SchemaRequest, response *datasource.SchemaResponse) {
+	response.Schema = schema.Schema{
+		Description: "Lists flespi streams matching all of the given filters.",
+		Attributes: map[string]schema.Attribute{
+			"name_regex": schema.StringAttribute{
+				Optional:    true,
+				Description: "Regular expression the stream name must match.",
+			},
+			"account_id": schema.Int64Attribute{
+				Optional:    true,
+				Description: "List streams as this (sub)account and only return the ones it owns.",
+			},
+			"enabled": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Only return enabled (true) or disabled (false) streams.",
+			},
+			"metadata_key": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only return streams having this metadata key.",
+			},
+			"metadata_value": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only return streams whose metadata_key has this value.",
+			},
+			"streams": schema.ListNestedAttribute{
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: streamDataSourceAttributes(),
+				},
+			},
+		},
+	}
+}
+
+func (d *gwStreamsDataSource) Read(ctx context.Context, request datasource.ReadRequest, response *datasource.ReadResponse) {
+	var config streamsDataSourceModel
+
+	response.Diagnostics.Append(request.Config.Get(ctx, &config)...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	filter, diags := common.NewListFilter(config.NameRegex, config.AccountId, config.Enabled, config.MetadataKey, config.MetadataValue)
+
+	response.Diagnostics.Append(diags...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	streams, err := listStreams(common.ForAccount(d.client, config.AccountId.ValueInt64()))
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error Reading Flespi Streams",
+			"Could not list Flespi streams: "+err.Error(),
+		)
+		return
+	}
+
+	config.Streams = []streamDataSourceModel{}
+
+	for _, stream := range streams {
+		if !filter.MatchName(stream.Name) || !filter.MatchAccountId(stream.AccountId) ||
+			!filter.MatchEnabled(stream.Enabled) || !filter.MatchMetadata(stream.metadata()) {
+			continue
+		}
+
+		model, diags := convertFlespiStreamToDataSourceModel(ctx, &stream)
+
+		response.Diagnostics.Append(diags...)
+
+		if response.Diagnostics.HasError() {
+			return
+		}
+
+		config.Streams = append(config.Streams, *model)
+	}
+
+	response.Diagnostics.Append(response.State.Set(ctx, &config)...)
+}
+
+func convertFlespiStreamToDataSourceModel(ctx context.Context, stream *streamObject) (*streamDataSourceModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	metadata, metaDiags := types.MapValueFrom(ctx, types.StringType, stream.metadata())
+	diags.Append(metaDiags...)
+
+	return &streamDataSourceModel{
+		Id:              types.Int64Value(stream.Id),
+		Name:            types.StringValue(stream.Name),
+		ProtocolId:      types.Int64Value(stream.ProtocolId),
+		Enabled:         types.BoolValue(stream.Enabled),
+		QueueTTL:        types.Int64Value(stream.QueueTTL),
+		ValidateMessage: types.StringValue(stream.ValidateMessage),
+		Configuration:   jsontypes.NewNormalizedValue(rawJSONString(stream.Configuration)),
+		Metadata:        metadata,
+		AccountId:       types.Int64Value(stream.AccountId),
+	}, diags
+}
@@ -0,0 +1,247 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-flespi/internal/provider/resources/common"
+
+	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
+	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	flespi "github.com/mixser/flespi-client"
+)
+
+var (
+	_ datasource.DataSource                     = &gwStreamProtocolDataSource{}
+	_ datasource.DataSourceWithConfigure        = &gwStreamProtocolDataSource{}
+	_ datasource.DataSourceWithConfigValidators = &gwStreamProtocolDataSource{}
+	_ datasource.DataSource                     = &gwStreamProtocolsDataSource{}
+	_ datasource.DataSourceWithConfigure        = &gwStreamProtocolsDataSource{}
+)
+
+type gwStreamProtocolDataSource struct {
+	client *flespi.Client
+}
+
+type gwStreamProtocolsDataSource struct {
+	client *flespi.Client
+}
+
+type streamProtocolDataSourceModel struct {
+	Id            types.Int64          `tfsdk:"id"`
+	Name          types.String         `tfsdk:"name"`
+	Title         types.String         `tfsdk:"title"`
+	Configuration jsontypes.Normalized `tfsdk:"configuration_schema"`
+}
+
+type streamProtocolsDataSourceModel struct {
+	NameRegex types.String                    `tfsdk:"name_regex"`
+	Protocols []streamProtocolDataSourceModel `tfsdk:"protocols"`
+}
+
+func NewStreamProtocolDataSource() datasource.DataSource {
+	return &gwStreamProtocolDataSource{}
+}
+
+func NewStreamProtocolsDataSource() datasource.DataSource {
+	return &gwStreamProtocolsDataSource{}
+}
+
+func streamProtocolDataSourceAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"id": schema.Int64Attribute{
+			Computed: true,
+		},
+		"name": schema.StringAttribute{
+			Computed: true,
+		},
+		"title": schema.StringAttribute{
+			Computed: true,
+		},
+		"configuration_schema": schema.StringAttribute{
+			Computed:    true,
+			CustomType:  jsontypes.NormalizedType{},
+			Description: "JSON schema of the stream configuration for this protocol.",
+		},
+	}
+}
+
+func (d *gwStreamProtocolDataSource) Metadata(ctx context.Context, request datasource.MetadataRequest, response *datasource.MetadataResponse) {
+	response.TypeName = request.ProviderTypeName + "_stream_protocol"
+}
+
+func (d *gwStreamProtocolDataSource) Configure(ctx context.Context, request datasource.ConfigureRequest, response *datasource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	client, ok := request.ProviderData.(*flespi.Client)
+
+	if !ok {
+		response.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *flespi.Client, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *gwStreamProtocolDataSource) Schema(ctx context.Context, request datasource.SchemaRequest, response *datasource.SchemaResponse) {
+	attributes := streamProtocolDataSourceAttributes()
+
+	attributes["id"] = schema.Int64Attribute{
+		Optional:    true,
+		Computed:    true,
+		Description: "ID of the stream protocol to look up.",
+	}
+	attributes["name"] = schema.StringAttribute{
+		Optional:    true,
+		Computed:    true,
+		Description: "Name of the stream protocol to look up (case-insensitive).",
+	}
+
+	response.Schema = schema.Schema{
+		Description: "Looks up a single flespi stream protocol by id or name.",
+		Attributes:  attributes,
+	}
+}
+
+func (d *gwStreamProtocolDataSource) ConfigValidators(ctx context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		datasourcevalidator.ExactlyOneOf(
+			path.MatchRoot("id"),
+			path.MatchRoot("name"),
+		),
+	}
+}
+
+func (d *gwStreamProtocolDataSource) Read(ctx context.Context, request datasource.ReadRequest, response *datasource.ReadResponse) {
+	var config streamProtocolDataSourceModel
+
+	response.Diagnostics.Append(request.Config.Get(ctx, &config)...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	var protocol *streamProtocol
+	var err error
+
+	if !config.Id.IsNull() {
+		protocol, err = getStreamProtocol(d.client, config.Id.ValueInt64())
+	} else {
+		var protocols []streamProtocol
+
+		protocols, err = listStreamProtocols(d.client)
+
+		if err == nil {
+			protocol, err = findStreamProtocol(protocols, config.Name.ValueString())
+		}
+	}
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error Reading Flespi Stream Protocol",
+			"Could not look up Flespi stream protocol: "+err.Error(),
+		)
+		return
+	}
+
+	response.Diagnostics.Append(response.State.Set(ctx, convertStreamProtocolToDataSourceModel(protocol))...)
+}
+
+func (d *gwStreamProtocolsDataSource) Metadata(ctx context.Context, request datasource.MetadataRequest, response *datasource.MetadataResponse) {
+	response.TypeName = request.ProviderTypeName + "_stream_protocols"
+}
+
+func (d *gwStreamProtocolsDataSource) Configure(ctx context.Context, request datasource.ConfigureRequest, response *datasource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	client, ok := request.ProviderData.(*flespi.Client)
+
+	if !ok {
+		response.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *flespi.Client, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *gwStreamProtocolsDataSource) Schema(ctx context.Context, request datasource.SchemaRequest, response *datasource.SchemaResponse) {
+	response.Schema = schema.Schema{
+		Description: "Lists flespi stream protocols.",
+		Attributes: map[string]schema.Attribute{
+			"name_regex": schema.StringAttribute{
+				Optional:    true,
+				Description: "Regular expression the protocol name must match.",
+			},
+			"protocols": schema.ListNestedAttribute{
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: streamProtocolDataSourceAttributes(),
+				},
+			},
+		},
+	}
+}
+
+func (d *gwStreamProtocolsDataSource) Read(ctx context.Context, request datasource.ReadRequest, response *datasource.ReadResponse) {
+	var config streamProtocolsDataSourceModel
+
+	response.Diagnostics.Append(request.Config.Get(ctx, &config)...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	filter, diags := common.NewListFilter(config.NameRegex, types.Int64Null(), types.BoolNull(), types.StringNull(), types.StringNull())
+
+	response.Diagnostics.Append(diags...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	protocols, err := listStreamProtocols(d.client)
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error Reading Flespi Stream Protocols",
+			"Could not list Flespi stream protocols: "+err.Error(),
+		)
+		return
+	}
+
+	config.Protocols = []streamProtocolDataSourceModel{}
+
+	for _, protocol := range protocols {
+		if !filter.MatchName(protocol.Name) {
+			continue
+		}
+
+		config.Protocols = append(config.Protocols, *convertStreamProtocolToDataSourceModel(&protocol))
+	}
+
+	response.Diagnostics.Append(response.State.Set(ctx, &config)...)
+}
+
+func convertStreamProtocolToDataSourceModel(protocol *streamProtocol) *streamProtocolDataSourceModel {
+	return &streamProtocolDataSourceModel{
+		Id:            types.Int64Value(protocol.Id),
+		Name:          types.StringValue(protocol.Name),
+		Title:         types.StringValue(protocol.Title),
+		Configuration: jsontypes.NewNormalizedValue(rawJSONString(protocol.Schema)),
+	}
+}
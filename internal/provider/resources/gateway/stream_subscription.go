@@ -0,0 +1,65 @@
+package gateway
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+)
+
+var streamDeviceSubscription = assignmentKind{
+	typeName:        "stream_device_subscription",
+	description:     "Subscribes a flespi stream to a device, so the stream forwards the device messages.",
+	parentEndpoint:  "streams",
+	parentAttribute: "stream_id",
+	parentTitle:     "stream",
+	childEndpoint:   "devices",
+	childAttribute:  "device_id",
+	childTitle:      "device",
+}
+
+func NewStreamDeviceSubscriptionResource() resource.Resource {
+	return &gwAssignmentResource{
+		kind: streamDeviceSubscription,
+	}
+}
+
+// NewStreamDeviceSubscriptionsResource subscribes a stream to a set of devices
+// in one resource.
+func NewStreamDeviceSubscriptionsResource() resource.Resource {
+	kind := streamDeviceSubscription
+	kind.typeName = "stream_device_subscriptions"
+	kind.description = "Subscribes a flespi stream to a set of devices, so the stream forwards their messages."
+
+	return &gwAssignmentSetResource{
+		kind:              kind,
+		childrenAttribute: "device_ids",
+	}
+}
+
+func NewStreamChannelSubscriptionResource() resource.Resource {
+	return &gwAssignmentResource{
+		kind: assignmentKind{
+			typeName:        "stream_channel_subscription",
+			description:     "Subscribes a flespi stream to a channel, so the stream forwards the messages received by the channel.",
+			parentEndpoint:  "streams",
+			parentAttribute: "stream_id",
+			parentTitle:     "stream",
+			childEndpoint:   "channels",
+			childAttribute:  "channel_id",
+			childTitle:      "channel",
+		},
+	}
+}
+
+func NewStreamGroupSubscriptionResource() resource.Resource {
+	return &gwAssignmentResource{
+		kind: assignmentKind{
+			typeName:        "stream_group_subscription",
+			description:     "Subscribes a flespi stream to a group, so the stream forwards the messages of every member of the group.",
+			parentEndpoint:  "streams",
+			parentAttribute: "stream_id",
+			parentTitle:     "stream",
+			childEndpoint:   "groups",
+			childAttribute:  "group_id",
+			childTitle:      "group",
+		},
+	}
+}
@@ -3,6 +3,7 @@ package platform
 import (
 	"context"
 	"fmt"
+	"terraform-provider-flespi/internal/provider/resources/common"
 
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -11,13 +12,15 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	flespi "github.com/mixser/flespi-client"
 	flespi_limit "github.com/mixser/flespi-client/resources/platform/limit"
 )
 
 var (
-	_ resource.Resource              = &platformLimitResource{}
-	_ resource.ResourceWithConfigure = &platformLimitResource{}
+	_ resource.Resource                = &platformLimitResource{}
+	_ resource.ResourceWithConfigure   = &platformLimitResource{}
+	_ resource.ResourceWithImportState = &platformLimitResource{}
 )
 
 func NewLimitResource() resource.Resource {
@@ -25,7 +28,8 @@ func NewLimitResource() resource.Resource {
 }
 
 type platformLimitResource struct {
-	client *flespi_limit.LimitClient
+	client   *flespi_limit.LimitClient
+	provider *flespi.Client
 }
 
 type limitResourceModel struct {
@@ -117,6 +121,12 @@ func (p *platformLimitResource) Configure(_ context.Context, req resource.Config
 	}
 
 	p.client = client.Limits
+	p.provider = client
+}
+
+// clientFor returns a limit client acting as the (sub)account that owns the limit.
+func (p *platformLimitResource) clientFor(accountId types.Int64) *flespi_limit.LimitClient {
+	return common.ForAccount(p.provider, accountId.ValueInt64()).Limits
 }
 
 func (p *platformLimitResource) Schema(ctx context.Context, request resource.SchemaRequest, response *resource.SchemaResponse) {
@@ -460,7 +470,13 @@ func (p *platformLimitResource) Read(ctx context.Context, request resource.ReadR
 		return
 	}
 
-	limit, err := p.client.Get(state.Id.ValueInt64())
+	limit, err := getLimit(common.ForAccount(p.provider, state.AccountId.ValueInt64()), state.Id.ValueInt64())
+
+	if common.IsNotFound(err) {
+		tflog.Warn(ctx, "Flespi limit not found, removing from state", map[string]interface{}{"id": state.Id.ValueInt64()})
+		response.State.RemoveResource(ctx)
+		return
+	}
 
 	if err != nil {
 		response.Diagnostics.AddError(
@@ -493,7 +509,7 @@ func (p *platformLimitResource) Update(ctx context.Context, request resource.Upd
 
 	var limit = p.convertResourceModelToFlespiLimit(plan)
 
-	_, err := p.client.Update(limit)
+	_, err := p.clientFor(plan.AccountId).Update(limit)
 
 	if err != nil {
 		response.Diagnostics.AddError(
@@ -503,7 +519,7 @@ func (p *platformLimitResource) Update(ctx context.Context, request resource.Upd
 		return
 	}
 
-	updatedLimit, err := p.client.Get(plan.Id.ValueInt64())
+	updatedLimit, err := getLimit(common.ForAccount(p.provider, plan.AccountId.ValueInt64()), plan.Id.ValueInt64())
 
 	if err != nil {
 		response.Diagnostics.AddError(
@@ -532,9 +548,9 @@ func (p *platformLimitResource) Delete(ctx context.Context, request resource.Del
 		return
 	}
 
-	err := p.client.DeleteById(state.Id.ValueInt64())
+	err := p.clientFor(state.AccountId).DeleteById(state.Id.ValueInt64())
 
-	if err != nil {
+	if err != nil && !common.IsNotFound(err) {
 		response.Diagnostics.AddError(
 			"Error Deleting Flespi Limit",
 			"Could not delete limit, unexpected error: "+err.Error(),
@@ -543,6 +559,10 @@ func (p *platformLimitResource) Delete(ctx context.Context, request resource.Del
 	}
 }
 
+func (p *platformLimitResource) ImportState(ctx context.Context, request resource.ImportStateRequest, response *resource.ImportStateResponse) {
+	common.ImportStateByAccountScopedId(ctx, request, response)
+}
+
 func (p *platformLimitResource) convertFlespiLimitToResourceModel(limit *flespi_limit.Limit) *limitResourceModel {
 	var state limitResourceModel
 
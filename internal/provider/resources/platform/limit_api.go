@@ -0,0 +1,40 @@
+package platform
+
+import (
+	"fmt"
+
+	flespi "github.com/mixser/flespi-client"
+	flespi_limit "github.com/mixser/flespi-client/resources/platform/limit"
+)
+
+type limitsResponse struct {
+	Limits []flespi_limit.Limit `json:"result"`
+}
+
+const limitFields = "id,name,description,blocking_duration,api_calls,api_traffic," +
+	"channels_count,channel_messages,channel_storage,channel_traffic,channel_connections," +
+	"containers_count,container_storage,cdns_count,cdn_storage,cdn_traffic," +
+	"devices_count,device_storage,device_media_traffic,device_media_storage," +
+	"streams_count,stream_storage,stream_traffic,modems_count," +
+	"mqtt_sessions,mqtt_messages,mqtt_session_storage,mqtt_retained_storage,mqtt_subscriptions," +
+	"sms_count,tokens_count,subaccounts_count,limits_count,realms_count,calcs_count,calcs_storage," +
+	"plugins_count,plugin_traffic,plugin_buffered_messages,groups_count," +
+	"webhooks_count,webhook_storage,webhook_traffic,grants_count,identity_providers_count,cid"
+
+// getLimit reads a limit like flespi_limit.GetLimit does, but reports an
+// empty result as a 404 instead of indexing past the end of it.
+func getLimit(client *flespi.Client, limitId int64) (*flespi_limit.Limit, error) {
+	response := limitsResponse{}
+
+	endpoint := fmt.Sprintf("platform/limits/%d?fields=%s", limitId, limitFields)
+
+	if err := client.RequestAPI("GET", endpoint, nil, &response); err != nil {
+		return nil, err
+	}
+
+	if len(response.Limits) == 0 {
+		return nil, &flespi.APIError{StatusCode: 404, Method: "GET", Endpoint: endpoint, Message: "limit not found"}
+	}
+
+	return &response.Limits[0], nil
+}
@@ -0,0 +1,331 @@
+package platform
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-flespi/internal/provider/resources/common"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	flespi "github.com/mixser/flespi-client"
+	flespi_limit "github.com/mixser/flespi-client/resources/platform/limit"
+)
+
+var (
+	_ datasource.DataSource                     = &platformLimitDataSource{}
+	_ datasource.DataSourceWithConfigure        = &platformLimitDataSource{}
+	_ datasource.DataSourceWithConfigValidators = &platformLimitDataSource{}
+	_ datasource.DataSource                     = &platformLimitsDataSource{}
+	_ datasource.DataSourceWithConfigure        = &platformLimitsDataSource{}
+)
+
+type platformLimitDataSource struct {
+	provider *flespi.Client
+}
+
+type platformLimitsDataSource struct {
+	provider *flespi.Client
+}
+
+type limitDataSourceModel struct {
+	Id               types.Int64  `tfsdk:"id"`
+	Name             types.String `tfsdk:"name"`
+	Description      types.String `tfsdk:"description"`
+	BlockingDuration types.Int64  `tfsdk:"blocking_duration"`
+	ApiCall          types.Int64  `tfsdk:"api_calls"`
+	ApiTraffic       types.Int64  `tfsdk:"api_traffic"`
+	DevicesCount     types.Int64  `tfsdk:"devices_count"`
+	ChannelsCount    types.Int64  `tfsdk:"channels_count"`
+	StreamsCount     types.Int64  `tfsdk:"streams_count"`
+	Metadata         types.Map    `tfsdk:"metadata"`
+	AccountId        types.Int64  `tfsdk:"account_id"`
+}
+
+type limitsDataSourceModel struct {
+	NameRegex     types.String           `tfsdk:"name_regex"`
+	AccountId     types.Int64            `tfsdk:"account_id"`
+	MetadataKey   types.String           `tfsdk:"metadata_key"`
+	MetadataValue types.String           `tfsdk:"metadata_value"`
+	Limits        []limitDataSourceModel `tfsdk:"limits"`
+}
+
+func NewLimitDataSource() datasource.DataSource {
+	return &platformLimitDataSource{}
+}
+
+func NewLimitsDataSource() datasource.DataSource {
+	return &platformLimitsDataSource{}
+}
+
+func limitDataSourceAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"id": schema.Int64Attribute{
+			Computed: true,
+		},
+		"name": schema.StringAttribute{
+			Computed: true,
+		},
+		"description": schema.StringAttribute{
+			Computed: true,
+		},
+		"blocking_duration": schema.Int64Attribute{
+			Computed: true,
+		},
+		"api_calls": schema.Int64Attribute{
+			Computed: true,
+		},
+		"api_traffic": schema.Int64Attribute{
+			Computed: true,
+		},
+		"devices_count": schema.Int64Attribute{
+			Computed: true,
+		},
+		"channels_count": schema.Int64Attribute{
+			Computed: true,
+		},
+		"streams_count": schema.Int64Attribute{
+			Computed: true,
+		},
+		"metadata": schema.MapAttribute{
+			Computed:    true,
+			ElementType: types.StringType,
+		},
+		"account_id": schema.Int64Attribute{
+			Computed: true,
+		},
+	}
+}
+
+func (d *platformLimitDataSource) Metadata(ctx context.Context, request datasource.MetadataRequest, response *datasource.MetadataResponse) {
+	response.TypeName = request.ProviderTypeName + "_limit"
+}
+
+func (d *platformLimitDataSource) Configure(ctx context.Context, request datasource.ConfigureRequest, response *datasource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	client, ok := request.ProviderData.(*flespi.Client)
+
+	if !ok {
+		response.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *flespi.Client, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+		)
+
+		return
+	}
+
+	d.provider = client
+}
+
+// clientFor returns a limit client acting as accountId, or as the provider account when it is null.
+func (d *platformLimitDataSource) clientFor(accountId types.Int64) *flespi_limit.LimitClient {
+	return common.ForAccount(d.provider, accountId.ValueInt64()).Limits
+}
+
+func (d *platformLimitDataSource) Schema(ctx context.Context, request datasource.SchemaRequest, response *datasource.SchemaResponse) {
+	attributes := limitDataSourceAttributes()
+
+	attributes["id"] = schema.Int64Attribute{
+		Optional:    true,
+		Computed:    true,
+		Description: "ID of the limit to look up.",
+	}
+	attributes["name"] = schema.StringAttribute{
+		Optional:    true,
+		Computed:    true,
+		Description: "Exact name of the limit to look up.",
+	}
+
+	attributes["account_id"] = schema.Int64Attribute{
+		Optional:    true,
+		Computed:    true,
+		Description: "(Sub)account to look the limit up in. Defaults to the provider's account_id.",
+	}
+
+	response.Schema = schema.Schema{
+		Description: "Looks up a single flespi limit by id or exact name.",
+		Attributes:  attributes,
+	}
+}
+
+func (d *platformLimitDataSource) ConfigValidators(ctx context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		datasourcevalidator.ExactlyOneOf(
+			path.MatchRoot("id"),
+			path.MatchRoot("name"),
+		),
+	}
+}
+
+func (d *platformLimitDataSource) Read(ctx context.Context, request datasource.ReadRequest, response *datasource.ReadResponse) {
+	var config limitDataSourceModel
+
+	response.Diagnostics.Append(request.Config.Get(ctx, &config)...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	client := d.clientFor(config.AccountId)
+
+	var limit *flespi_limit.Limit
+	var err error
+
+	if !config.Id.IsNull() {
+		limit, err = getLimit(common.ForAccount(d.provider, config.AccountId.ValueInt64()), config.Id.ValueInt64())
+	} else {
+		var limits []flespi_limit.Limit
+
+		limits, err = client.List()
+
+		if err == nil {
+			limit, err = common.FindByName(limits, config.Name.ValueString(),
+				func(item flespi_limit.Limit) string { return item.Name },
+				func(item flespi_limit.Limit) int64 { return item.Id },
+			)
+		}
+	}
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error Reading Flespi Limit",
+			"Could not look up Flespi limit: "+err.Error(),
+		)
+		return
+	}
+
+	model, diags := convertFlespiLimitToDataSourceModel(ctx, limit)
+
+	response.Diagnostics.Append(diags...)
+	response.Diagnostics.Append(response.State.Set(ctx, model)...)
+}
+
+func (d *platformLimitsDataSource) Metadata(ctx context.Context, request datasource.MetadataRequest, response *datasource.MetadataResponse) {
+	response.TypeName = request.ProviderTypeName + "_limits"
+}
+
+func (d *platformLimitsDataSource) Configure(ctx context.Context, request datasource.ConfigureRequest, response *datasource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	client, ok := request.ProviderData.(*flespi.Client)
+
+	if !ok {
+		response.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *flespi.Client, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+		)
+
+		return
+	}
+
+	d.provider = client
+}
+
+// clientFor returns a limit client acting as accountId, or as the provider account when it is null.
+func (d *platformLimitsDataSource) clientFor(accountId types.Int64) *flespi_limit.LimitClient {
+	return common.ForAccount(d.provider, accountId.ValueInt64()).Limits
+}
+
+func (d *platformLimitsDataSource) Schema(ctx context.Context, request datasource.SchemaRequest, response *datasource.SchemaResponse) {
+	response.Schema = schema.Schema{
+		Description: "Lists flespi limits matching all of the given filters.",
+		Attributes: map[string]schema.Attribute{
+			"name_regex": schema.StringAttribute{
+				Optional:    true,
+				Description: "Regular expression the limit name must match.",
+			},
+			"account_id": schema.Int64Attribute{
+				Optional:    true,
+				Description: "List limits as this (sub)account and only return the ones it owns.",
+			},
+			"metadata_key": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only return limits having this metadata key.",
+			},
+			"metadata_value": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only return limits whose metadata_key has this value.",
+			},
+			"limits": schema.ListNestedAttribute{
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: limitDataSourceAttributes(),
+				},
+			},
+		},
+	}
+}
+
+func (d *platformLimitsDataSource) Read(ctx context.Context, request datasource.ReadRequest, response *datasource.ReadResponse) {
+	var config limitsDataSourceModel
+
+	response.Diagnostics.Append(request.Config.Get(ctx, &config)...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	filter, diags := common.NewListFilter(config.NameRegex, config.AccountId, types.BoolNull(), config.MetadataKey, config.MetadataValue)
+
+	response.Diagnostics.Append(diags...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	limits, err := d.clientFor(config.AccountId).List()
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error Reading Flespi Limits",
+			"Could not list Flespi limits: "+err.Error(),
+		)
+		return
+	}
+
+	config.Limits = []limitDataSourceModel{}
+
+	for _, limit := range limits {
+		if !filter.MatchName(limit.Name) || !filter.MatchAccountId(limit.AccountId) || !filter.MatchMetadata(limit.Metadata) {
+			continue
+		}
+
+		model, diags := convertFlespiLimitToDataSourceModel(ctx, &limit)
+
+		response.Diagnostics.Append(diags...)
+
+		if response.Diagnostics.HasError() {
+			return
+		}
+
+		config.Limits = append(config.Limits, *model)
+	}
+
+	response.Diagnostics.Append(response.State.Set(ctx, &config)...)
+}
+
+func convertFlespiLimitToDataSourceModel(ctx context.Context, limit *flespi_limit.Limit) (*limitDataSourceModel, diag.Diagnostics) {
+	metadata, diags := types.MapValueFrom(ctx, types.StringType, limit.Metadata)
+
+	return &limitDataSourceModel{
+		Id:               types.Int64Value(limit.Id),
+		Name:             types.StringValue(limit.Name),
+		Description:      types.StringValue(limit.Description),
+		BlockingDuration: types.Int64Value(int64(limit.BlockingDuration)),
+		ApiCall:          types.Int64Value(limit.ApiCall),
+		ApiTraffic:       types.Int64Value(limit.ApiTraffic),
+		DevicesCount:     types.Int64Value(limit.DevicesCount),
+		ChannelsCount:    types.Int64Value(limit.ChannelsCount),
+		StreamsCount:     types.Int64Value(limit.StreamsCount),
+		Metadata:         metadata,
+		AccountId:        types.Int64Value(limit.AccountId),
+	}, diags
+}
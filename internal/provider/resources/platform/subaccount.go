@@ -3,19 +3,22 @@ package platform
 import (
 	"context"
 	"fmt"
+	"terraform-provider-flespi/internal/provider/resources/common"
 
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	flespi "github.com/mixser/flespi-client"
 	flespi_subaccount "github.com/mixser/flespi-client/resources/platform/subaccount"
 )
 
 var (
-	_ resource.Resource              = &platformSubaccountResource{}
-	_ resource.ResourceWithConfigure = &platformSubaccountResource{}
+	_ resource.Resource                = &platformSubaccountResource{}
+	_ resource.ResourceWithConfigure   = &platformSubaccountResource{}
+	_ resource.ResourceWithImportState = &platformSubaccountResource{}
 )
 
 func NewSubaccountResource() resource.Resource {
@@ -23,7 +26,8 @@ func NewSubaccountResource() resource.Resource {
 }
 
 type platformSubaccountResource struct {
-	client *flespi_subaccount.SubaccountClient
+	client   *flespi_subaccount.SubaccountClient
+	provider *flespi.Client
 }
 
 type subaccountResourceModel struct {
@@ -48,6 +52,12 @@ func (p *platformSubaccountResource) Configure(ctx context.Context, request reso
 	}
 
 	p.client = client.Subaccounts
+	p.provider = client
+}
+
+// clientFor returns a subaccount client acting as the (sub)account that owns the subaccount.
+func (p *platformSubaccountResource) clientFor(accountId types.Int64) *flespi_subaccount.SubaccountClient {
+	return common.ForAccount(p.provider, accountId.ValueInt64()).Subaccounts
 }
 
 func (p *platformSubaccountResource) Metadata(ctx context.Context, request resource.MetadataRequest, response *resource.MetadataResponse) {
@@ -118,7 +128,13 @@ func (p *platformSubaccountResource) Read(ctx context.Context, request resource.
 		return
 	}
 
-	subaccount, err := p.client.Get(state.Id.ValueInt64())
+	subaccount, err := getSubaccount(common.ForAccount(p.provider, state.AccountId.ValueInt64()), state.Id.ValueInt64())
+
+	if common.IsNotFound(err) {
+		tflog.Warn(ctx, "Flespi subaccount not found, removing from state", map[string]interface{}{"id": state.Id.ValueInt64()})
+		response.State.RemoveResource(ctx)
+		return
+	}
 
 	if err != nil {
 		response.Diagnostics.AddError(
@@ -152,7 +168,7 @@ func (p *platformSubaccountResource) Update(ctx context.Context, request resourc
 
 	subaccount := p.convertResourceModelToFlespiSubaccount(plan)
 
-	_, err := p.client.Update(subaccount)
+	_, err := p.clientFor(plan.AccountId).Update(subaccount)
 
 	if err != nil {
 		response.Diagnostics.AddError(
@@ -162,7 +178,7 @@ func (p *platformSubaccountResource) Update(ctx context.Context, request resourc
 		return
 	}
 
-	updatedSubaccount, err := p.client.Get(plan.Id.ValueInt64())
+	updatedSubaccount, err := getSubaccount(common.ForAccount(p.provider, plan.AccountId.ValueInt64()), plan.Id.ValueInt64())
 
 	if err != nil {
 		response.Diagnostics.AddError(
@@ -190,9 +206,9 @@ func (p *platformSubaccountResource) Delete(ctx context.Context, request resourc
 		return
 	}
 
-	err := p.client.DeleteById(state.Id.ValueInt64())
+	err := p.clientFor(state.AccountId).DeleteById(state.Id.ValueInt64())
 
-	if err != nil {
+	if err != nil && !common.IsNotFound(err) {
 		response.Diagnostics.AddError(
 			"Error Deleting Flespi Subaccount",
 			"Could not delete subaccount, unexpected error: "+err.Error(),
@@ -201,6 +217,10 @@ func (p *platformSubaccountResource) Delete(ctx context.Context, request resourc
 	}
 }
 
+func (p *platformSubaccountResource) ImportState(ctx context.Context, request resource.ImportStateRequest, response *resource.ImportStateResponse) {
+	common.ImportStateByAccountScopedId(ctx, request, response)
+}
+
 func (p *platformSubaccountResource) convertFlespiSubaccountToResourceModel(subaccount *flespi_subaccount.Subaccount) *subaccountResourceModel {
 	return &subaccountResourceModel{
 		Id:        types.Int64Value(subaccount.Id),
@@ -0,0 +1,32 @@
+package platform
+
+import (
+	"fmt"
+
+	flespi "github.com/mixser/flespi-client"
+	flespi_subaccount "github.com/mixser/flespi-client/resources/platform/subaccount"
+)
+
+type subaccountsResponse struct {
+	Subaccounts []flespi_subaccount.Subaccount `json:"result"`
+}
+
+const subaccountFields = "id,name,limit_id,metadata,cid"
+
+// getSubaccount reads a subaccount like flespi_subaccount.GetSubaccount does,
+// but reports an empty result as a 404 instead of indexing past the end of it.
+func getSubaccount(client *flespi.Client, subaccountId int64) (*flespi_subaccount.Subaccount, error) {
+	response := subaccountsResponse{}
+
+	endpoint := fmt.Sprintf("platform/subaccounts/%d?fields=%s", subaccountId, subaccountFields)
+
+	if err := client.RequestAPI("GET", endpoint, nil, &response); err != nil {
+		return nil, err
+	}
+
+	if len(response.Subaccounts) == 0 {
+		return nil, &flespi.APIError{StatusCode: 404, Method: "GET", Endpoint: endpoint, Message: "subaccount not found"}
+	}
+
+	return &response.Subaccounts[0], nil
+}
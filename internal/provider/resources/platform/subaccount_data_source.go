@@ -0,0 +1,301 @@
+package platform
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-flespi/internal/provider/resources/common"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	flespi "github.com/mixser/flespi-client"
+	flespi_subaccount "github.com/mixser/flespi-client/resources/platform/subaccount"
+)
+
+var (
+	_ datasource.DataSource                     = &platformSubaccountDataSource{}
+	_ datasource.DataSourceWithConfigure        = &platformSubaccountDataSource{}
+	_ datasource.DataSourceWithConfigValidators = &platformSubaccountDataSource{}
+	_ datasource.DataSource                     = &platformSubaccountsDataSource{}
+	_ datasource.DataSourceWithConfigure        = &platformSubaccountsDataSource{}
+)
+
+type platformSubaccountDataSource struct {
+	provider *flespi.Client
+}
+
+type platformSubaccountsDataSource struct {
+	provider *flespi.Client
+}
+
+type subaccountDataSourceModel struct {
+	Id        types.Int64  `tfsdk:"id"`
+	Name      types.String `tfsdk:"name"`
+	LimitId   types.Int64  `tfsdk:"limit_id"`
+	Metadata  types.Map    `tfsdk:"metadata"`
+	AccountId types.Int64  `tfsdk:"account_id"`
+}
+
+type subaccountsDataSourceModel struct {
+	NameRegex     types.String                `tfsdk:"name_regex"`
+	AccountId     types.Int64                 `tfsdk:"account_id"`
+	MetadataKey   types.String                `tfsdk:"metadata_key"`
+	MetadataValue types.String                `tfsdk:"metadata_value"`
+	Subaccounts   []subaccountDataSourceModel `tfsdk:"subaccounts"`
+}
+
+func NewSubaccountDataSource() datasource.DataSource {
+	return &platformSubaccountDataSource{}
+}
+
+func NewSubaccountsDataSource() datasource.DataSource {
+	return &platformSubaccountsDataSource{}
+}
+
+func subaccountDataSourceAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"id": schema.Int64Attribute{
+			Computed: true,
+		},
+		"name": schema.StringAttribute{
+			Computed: true,
+		},
+		"limit_id": schema.Int64Attribute{
+			Computed: true,
+		},
+		"metadata": schema.MapAttribute{
+			Computed:    true,
+			ElementType: types.StringType,
+		},
+		"account_id": schema.Int64Attribute{
+			Computed: true,
+		},
+	}
+}
+
+func (d *platformSubaccountDataSource) Metadata(ctx context.Context, request datasource.MetadataRequest, response *datasource.MetadataResponse) {
+	response.TypeName = request.ProviderTypeName + "_subaccount"
+}
+
+func (d *platformSubaccountDataSource) Configure(ctx context.Context, request datasource.ConfigureRequest, response *datasource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	client, ok := request.ProviderData.(*flespi.Client)
+
+	if !ok {
+		response.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *flespi.Client, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+		)
+
+		return
+	}
+
+	d.provider = client
+}
+
+// clientFor returns a subaccount client acting as accountId, or as the provider account when it is null.
+func (d *platformSubaccountDataSource) clientFor(accountId types.Int64) *flespi_subaccount.SubaccountClient {
+	return common.ForAccount(d.provider, accountId.ValueInt64()).Subaccounts
+}
+
+func (d *platformSubaccountDataSource) Schema(ctx context.Context, request datasource.SchemaRequest, response *datasource.SchemaResponse) {
+	attributes := subaccountDataSourceAttributes()
+
+	attributes["id"] = schema.Int64Attribute{
+		Optional:    true,
+		Computed:    true,
+		Description: "ID of the subaccount to look up.",
+	}
+	attributes["name"] = schema.StringAttribute{
+		Optional:    true,
+		Computed:    true,
+		Description: "Exact name of the subaccount to look up.",
+	}
+
+	attributes["account_id"] = schema.Int64Attribute{
+		Optional:    true,
+		Computed:    true,
+		Description: "(Sub)account to look the subaccount up in. Defaults to the provider's account_id.",
+	}
+
+	response.Schema = schema.Schema{
+		Description: "Looks up a single flespi subaccount by id or exact name.",
+		Attributes:  attributes,
+	}
+}
+
+func (d *platformSubaccountDataSource) ConfigValidators(ctx context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		datasourcevalidator.ExactlyOneOf(
+			path.MatchRoot("id"),
+			path.MatchRoot("name"),
+		),
+	}
+}
+
+func (d *platformSubaccountDataSource) Read(ctx context.Context, request datasource.ReadRequest, response *datasource.ReadResponse) {
+	var config subaccountDataSourceModel
+
+	response.Diagnostics.Append(request.Config.Get(ctx, &config)...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	client := d.clientFor(config.AccountId)
+
+	var subaccount *flespi_subaccount.Subaccount
+	var err error
+
+	if !config.Id.IsNull() {
+		subaccount, err = getSubaccount(common.ForAccount(d.provider, config.AccountId.ValueInt64()), config.Id.ValueInt64())
+	} else {
+		var subaccounts []flespi_subaccount.Subaccount
+
+		subaccounts, err = client.List()
+
+		if err == nil {
+			subaccount, err = common.FindByName(subaccounts, config.Name.ValueString(),
+				func(item flespi_subaccount.Subaccount) string { return item.Name },
+				func(item flespi_subaccount.Subaccount) int64 { return item.Id },
+			)
+		}
+	}
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error Reading Flespi Subaccount",
+			"Could not look up Flespi subaccount: "+err.Error(),
+		)
+		return
+	}
+
+	model, diags := convertFlespiSubaccountToDataSourceModel(ctx, subaccount)
+
+	response.Diagnostics.Append(diags...)
+	response.Diagnostics.Append(response.State.Set(ctx, model)...)
+}
+
+func (d *platformSubaccountsDataSource) Metadata(ctx context.Context, request datasource.MetadataRequest, response *datasource.MetadataResponse) {
+	response.TypeName = request.ProviderTypeName + "_subaccounts"
+}
+
+func (d *platformSubaccountsDataSource) Configure(ctx context.Context, request datasource.ConfigureRequest, response *datasource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	client, ok := request.ProviderData.(*flespi.Client)
+
+	if !ok {
+		response.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *flespi.Client, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+		)
+
+		return
+	}
+
+	d.provider = client
+}
+
+// clientFor returns a subaccount client acting as accountId, or as the provider account when it is null.
+func (d *platformSubaccountsDataSource) clientFor(accountId types.Int64) *flespi_subaccount.SubaccountClient {
+	return common.ForAccount(d.provider, accountId.ValueInt64()).Subaccounts
+}
+
+func (d *platformSubaccountsDataSource) Schema(ctx context.Context, request datasource.SchemaRequest, response *datasource.SchemaResponse) {
+	response.Schema = schema.Schema{
+		Description: "Lists flespi subaccounts matching all of the given filters.",
+		Attributes: map[string]schema.Attribute{
+			"name_regex": schema.StringAttribute{
+				Optional:    true,
+				Description: "Regular expression the subaccount name must match.",
+			},
+			"account_id": schema.Int64Attribute{
+				Optional:    true,
+				Description: "List subaccounts as this (sub)account and only return the ones it owns.",
+			},
+			"metadata_key": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only return subaccounts having this metadata key.",
+			},
+			"metadata_value": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only return subaccounts whose metadata_key has this value.",
+			},
+			"subaccounts": schema.ListNestedAttribute{
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: subaccountDataSourceAttributes(),
+				},
+			},
+		},
+	}
+}
+
+func (d *platformSubaccountsDataSource) Read(ctx context.Context, request datasource.ReadRequest, response *datasource.ReadResponse) {
+	var config subaccountsDataSourceModel
+
+	response.Diagnostics.Append(request.Config.Get(ctx, &config)...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	filter, diags := common.NewListFilter(config.NameRegex, config.AccountId, types.BoolNull(), config.MetadataKey, config.MetadataValue)
+
+	response.Diagnostics.Append(diags...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	subaccounts, err := d.clientFor(config.AccountId).List()
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error Reading Flespi Subaccounts",
+			"Could not list Flespi subaccounts: "+err.Error(),
+		)
+		return
+	}
+
+	config.Subaccounts = []subaccountDataSourceModel{}
+
+	for _, subaccount := range subaccounts {
+		if !filter.MatchName(subaccount.Name) || !filter.MatchAccountId(subaccount.AccountId) || !filter.MatchMetadata(subaccount.Metadata) {
+			continue
+		}
+
+		model, diags := convertFlespiSubaccountToDataSourceModel(ctx, &subaccount)
+
+		response.Diagnostics.Append(diags...)
+
+		if response.Diagnostics.HasError() {
+			return
+		}
+
+		config.Subaccounts = append(config.Subaccounts, *model)
+	}
+
+	response.Diagnostics.Append(response.State.Set(ctx, &config)...)
+}
+
+func convertFlespiSubaccountToDataSourceModel(ctx context.Context, subaccount *flespi_subaccount.Subaccount) (*subaccountDataSourceModel, diag.Diagnostics) {
+	metadata, diags := types.MapValueFrom(ctx, types.StringType, subaccount.Metadata)
+
+	return &subaccountDataSourceModel{
+		Id:        types.Int64Value(subaccount.Id),
+		Name:      types.StringValue(subaccount.Name),
+		LimitId:   types.Int64Value(subaccount.LimitId),
+		Metadata:  metadata,
+		AccountId: types.Int64Value(subaccount.AccountId),
+	}, diags
+}
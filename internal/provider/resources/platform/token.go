@@ -4,30 +4,75 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"terraform-provider-flespi/internal/provider/resources/common"
 
 	"github.com/hashicorp/terraform-plugin-framework-jsontypes/jsontypes"
-	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	schemavalidator "github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	flespi "github.com/mixser/flespi-client"
 	flespi_token "github.com/mixser/flespi-client/resources/gateway/token"
 )
 
 var (
-	_ resource.Resource              = &platformTokenResource{}
-	_ resource.ResourceWithConfigure = &platformTokenResource{}
+	_ resource.Resource                   = &platformTokenResource{}
+	_ resource.ResourceWithConfigure      = &platformTokenResource{}
+	_ resource.ResourceWithImportState    = &platformTokenResource{}
+	_ resource.ResourceWithValidateConfig = &platformTokenResource{}
+	_ resource.ResourceWithUpgradeState   = &platformTokenResource{}
+	_ resource.ResourceWithModifyPlan     = &platformTokenResource{}
 )
 
 type platformTokenResource struct {
-	client *flespi_token.TokenClient
+	client   *flespi_token.TokenClient
+	provider *flespi.Client
 }
 
 type tokenResourceModel struct {
+	Id        types.Int64  `tfsdk:"id"`
+	Key       types.String `tfsdk:"key"`
+	Info      types.String `tfsdk:"info"`
+	Enabled   types.Bool   `tfsdk:"enabled"`
+	Expire    types.Int64  `tfsdk:"expire"`
+	TTL       types.Int64  `tfsdk:"ttl"`
+	AccountId types.Int64  `tfsdk:"account_id"`
+	Metadata  types.Map    `tfsdk:"metadata"`
+	Access    types.Object `tfsdk:"access"`
+
+	AccessJSON jsontypes.Normalized `tfsdk:"access_json"`
+
+	PreserveUnmanagedMetadata types.Bool `tfsdk:"preserve_unmanaged_metadata"`
+}
+
+// access returns the access to send to flespi, taken from access_json when it
+// is set, or nil when access is left to flespi.
+func (data tokenResourceModel) access(ctx context.Context) (*flespi_token.TokenAccess, diag.Diagnostics) {
+	if data.AccessJSON.IsNull() || data.AccessJSON.IsUnknown() {
+		return tokenAccessToFlespi(ctx, data.Access)
+	}
+
+	var diags diag.Diagnostics
+	var access flespi_token.TokenAccess
+
+	if err := json.Unmarshal([]byte(data.AccessJSON.ValueString()), &access); err != nil {
+		diags.AddAttributeError(path.Root("access_json"), "Invalid access_json", err.Error())
+		return nil, diags
+	}
+
+	return &access, diags
+}
+
+// tokenResourceModelV0 is the state of schema version 0, which took access as
+// raw JSON.
+type tokenResourceModelV0 struct {
 	Id        types.Int64          `tfsdk:"id"`
 	Key       types.String         `tfsdk:"key"`
 	Info      types.String         `tfsdk:"info"`
@@ -37,6 +82,8 @@ type tokenResourceModel struct {
 	AccountId types.Int64          `tfsdk:"account_id"`
 	Metadata  types.Map            `tfsdk:"metadata"`
 	Access    jsontypes.Normalized `tfsdk:"access"`
+
+	PreserveUnmanagedMetadata types.Bool `tfsdk:"preserve_unmanaged_metadata"`
 }
 
 func NewTokenResource() resource.Resource {
@@ -64,10 +111,17 @@ func (p *platformTokenResource) Configure(ctx context.Context, request resource.
 	}
 
 	p.client = client.Tokens
+	p.provider = client
+}
+
+// clientFor returns a token client acting as the (sub)account that owns the token.
+func (p *platformTokenResource) clientFor(accountId types.Int64) *flespi_token.TokenClient {
+	return common.ForAccount(p.provider, accountId.ValueInt64()).Tokens
 }
 
 func (p *platformTokenResource) Schema(ctx context.Context, request resource.SchemaRequest, response *resource.SchemaResponse) {
 	response.Schema = schema.Schema{
+		Version: 1,
 		Attributes: map[string]schema.Attribute{
 			"id": schema.Int64Attribute{
 				Computed: true,
@@ -112,16 +166,63 @@ func (p *platformTokenResource) Schema(ctx context.Context, request resource.Sch
 				ElementType: types.StringType,
 				Description: "Token metadata",
 			},
-			"access": schema.StringAttribute{
-				Optional:    true,
-				Computed:    true,
-				CustomType:  jsontypes.NormalizedType{},
-				Description: "Token access permissions as JSON. Use jsonencode() in HCL. Example: jsonencode({type=1}) for master, jsonencode({type=0}) for standard, jsonencode({type=2, acl=[{uri=\"gw/devices\", methods=[\"GET\"], ids=\"all\"}]}) for ACL.",
+			"preserve_unmanaged_metadata": common.PreserveUnmanagedMetadataAttribute(),
+			"access":                      tokenAccessAttribute(),
+			"access_json": schema.StringAttribute{
+				Optional:           true,
+				CustomType:         jsontypes.NormalizedType{},
+				Description:        "Token access as JSON in the flespi API format, with numeric access types. Conflicts with access, which shows the resulting permissions.",
+				DeprecationMessage: "Use the access attribute instead. access_json is kept for configurations written for the JSON form of access and will be removed in the next major release.",
+				Validators: []schemavalidator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("access")),
+				},
 			},
 		},
 	}
 }
 
+// ValidateConfig checks the access block during plan, so a type without its
+// acl or fields on the wrong uri fail before the API is called.
+func (p *platformTokenResource) ValidateConfig(ctx context.Context, request resource.ValidateConfigRequest, response *resource.ValidateConfigResponse) {
+	var accessJSON jsontypes.Normalized
+
+	if !request.Config.GetAttribute(ctx, path.Root("access_json"), &accessJSON).HasError() && !accessJSON.IsNull() && !accessJSON.IsUnknown() {
+		_, diags := tokenResourceModel{AccessJSON: accessJSON}.access(ctx)
+		response.Diagnostics.Append(diags...)
+	}
+
+	var access *tokenAccessModel
+
+	if request.Config.GetAttribute(ctx, path.Root("access"), &access).HasError() || access == nil {
+		return
+	}
+
+	access.validate(&response.Diagnostics)
+}
+
+// ModifyPlan marks access as unknown when a changed access_json is about to
+// replace it, since the permissions kept in state no longer apply.
+func (p *platformTokenResource) ModifyPlan(ctx context.Context, request resource.ModifyPlanRequest, response *resource.ModifyPlanResponse) {
+	if request.State.Raw.IsNull() || request.Plan.Raw.IsNull() {
+		return
+	}
+
+	var planned, prior jsontypes.Normalized
+
+	response.Diagnostics.Append(request.Plan.GetAttribute(ctx, path.Root("access_json"), &planned)...)
+	response.Diagnostics.Append(request.State.GetAttribute(ctx, path.Root("access_json"), &prior)...)
+
+	if response.Diagnostics.HasError() || planned.IsNull() || planned.Equal(prior) {
+		return
+	}
+
+	if equal, _ := planned.StringSemanticEquals(ctx, prior); equal {
+		return
+	}
+
+	response.Diagnostics.Append(response.Plan.SetAttribute(ctx, path.Root("access"), types.ObjectUnknown(tokenAccessAttribute().GetType().(types.ObjectType).AttrTypes))...)
+}
+
 func (p *platformTokenResource) Create(ctx context.Context, request resource.CreateRequest, response *resource.CreateResponse) {
 	var data *tokenResourceModel
 
@@ -147,13 +248,16 @@ func (p *platformTokenResource) Create(ctx context.Context, request resource.Cre
 		options = append(options, flespi_token.WithAccountId(data.AccountId.ValueInt64()))
 	}
 
-	if !data.Access.IsNull() && !data.Access.IsUnknown() {
-		var access flespi_token.TokenAccess
-		if err := json.Unmarshal([]byte(data.Access.ValueString()), &access); err != nil {
-			response.Diagnostics.AddError("Invalid access JSON", err.Error())
-			return
-		}
-		options = append(options, flespi_token.WithAccess(access))
+	access, diags := data.access(ctx)
+	response.Diagnostics.Append(diags...)
+
+	if access != nil {
+		options = append(options, flespi_token.WithAccess(*access))
+	}
+
+	if metadata, ok, diags := common.MetadataElements(ctx, data.Metadata); ok {
+		response.Diagnostics.Append(diags...)
+		options = append(options, flespi_token.WithMetadata(metadata))
 	}
 
 	tokenInstance, err := p.client.Create(data.Info.ValueString(), options...)
@@ -166,7 +270,8 @@ func (p *platformTokenResource) Create(ctx context.Context, request resource.Cre
 		return
 	}
 
-	result, diags := p.convertFlespiTokenToResourceModel(tokenInstance)
+	result, diags := p.convertFlespiTokenToResourceModel(ctx, tokenInstance, data.Metadata, data.PreserveUnmanagedMetadata)
+	result.AccessJSON = data.AccessJSON
 
 	response.Diagnostics.Append(diags...)
 	response.Diagnostics.Append(response.State.Set(ctx, &result)...)
@@ -182,7 +287,13 @@ func (p *platformTokenResource) Read(ctx context.Context, request resource.ReadR
 		return
 	}
 
-	token, err := p.client.Get(state.Id.ValueInt64())
+	token, err := getToken(common.ForAccount(p.provider, state.AccountId.ValueInt64()), state.Id.ValueInt64())
+
+	if common.IsNotFound(err) {
+		tflog.Warn(ctx, "Flespi token not found, removing from state", map[string]interface{}{"id": state.Id.ValueInt64()})
+		response.State.RemoveResource(ctx)
+		return
+	}
 
 	if err != nil {
 		response.Diagnostics.AddError(
@@ -196,7 +307,8 @@ func (p *platformTokenResource) Read(ctx context.Context, request resource.ReadR
 	// Preserve the key from state since it's not returned by the API after creation
 	token.Key = state.Key.ValueString()
 
-	result, diags := p.convertFlespiTokenToResourceModel(token)
+	result, diags := p.convertFlespiTokenToResourceModel(ctx, token, state.Metadata, state.PreserveUnmanagedMetadata)
+	result.AccessJSON = state.AccessJSON
 
 	response.Diagnostics.Append(diags...)
 	response.Diagnostics.Append(response.State.Set(ctx, result)...)
@@ -213,13 +325,48 @@ func (p *platformTokenResource) Update(ctx context.Context, request resource.Upd
 		return
 	}
 
-	token, diags := p.convertResourceModelToFlespiToken(plan)
+	token, diags := p.convertResourceModelToFlespiToken(ctx, plan)
 	response.Diagnostics.Append(diags...)
 	if response.Diagnostics.HasError() {
 		return
 	}
 
-	_, err := p.client.Update(token)
+	token.AccountId = state.AccountId.ValueInt64()
+
+	planned, plannedKnown, diags := common.MetadataElements(ctx, plan.Metadata)
+	response.Diagnostics.Append(diags...)
+
+	prior, _, diags := common.MetadataElements(ctx, state.Metadata)
+	response.Diagnostics.Append(diags...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	var metadata *map[string]string
+
+	if plannedKnown {
+		var remote map[string]string
+
+		if plan.PreserveUnmanagedMetadata.ValueBool() {
+			current, err := getToken(common.ForAccount(p.provider, state.AccountId.ValueInt64()), state.Id.ValueInt64())
+
+			if err != nil {
+				response.Diagnostics.AddError(
+					"Error Reading Flespi Token",
+					"Could not read current token metadata: "+err.Error(),
+				)
+				return
+			}
+
+			remote = current.Metadata
+		}
+
+		merged := common.MetadataToSend(planned, prior, remote, plan.PreserveUnmanagedMetadata.ValueBool())
+		metadata = &merged
+	}
+
+	_, err := updateToken(p.provider, token, metadata)
 
 	if err != nil {
 		response.Diagnostics.AddError(
@@ -229,19 +376,21 @@ func (p *platformTokenResource) Update(ctx context.Context, request resource.Upd
 		return
 	}
 
-	updatedToken, err := p.client.Get(plan.Id.ValueInt64())
+	updatedToken, err := getToken(common.ForAccount(p.provider, state.AccountId.ValueInt64()), plan.Id.ValueInt64())
 
 	if err != nil {
 		response.Diagnostics.AddError(
 			"Error Reading Flespi Token",
 			"Could not read token Id: "+plan.Id.String()+": "+err.Error(),
 		)
+		return
 	}
 
 	// Preserve the key from state
 	updatedToken.Key = state.Key.ValueString()
 
-	result, diags := p.convertFlespiTokenToResourceModel(updatedToken)
+	result, diags := p.convertFlespiTokenToResourceModel(ctx, updatedToken, common.OwnedMetadata(plan.Metadata, state.Metadata), plan.PreserveUnmanagedMetadata)
+	result.AccessJSON = plan.AccessJSON
 
 	response.Diagnostics.Append(diags...)
 	response.Diagnostics.Append(response.State.Set(ctx, result)...)
@@ -258,9 +407,9 @@ func (p *platformTokenResource) Delete(ctx context.Context, request resource.Del
 		return
 	}
 
-	err := p.client.DeleteById(state.Id.ValueInt64())
+	err := p.clientFor(state.AccountId).DeleteById(state.Id.ValueInt64())
 
-	if err != nil {
+	if err != nil && !common.IsNotFound(err) {
 		response.Diagnostics.AddError(
 			"Error Deleting Flespi Token",
 			"Could not delete token, unexpected error: "+err.Error(),
@@ -269,7 +418,70 @@ func (p *platformTokenResource) Delete(ctx context.Context, request resource.Del
 	}
 }
 
-func (p *platformTokenResource) convertFlespiTokenToResourceModel(token *flespi_token.Token) (*tokenResourceModel, diag.Diagnostics) {
+func (p *platformTokenResource) ImportState(ctx context.Context, request resource.ImportStateRequest, response *resource.ImportStateResponse) {
+	common.ImportStateByAccountScopedId(ctx, request, response)
+}
+
+func (p *platformTokenResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		// Version 0 stored access as JSON with numeric access types.
+		0: {
+			PriorSchema: &schema.Schema{
+				Attributes: map[string]schema.Attribute{
+					"id":                          schema.Int64Attribute{Computed: true},
+					"key":                         schema.StringAttribute{Computed: true, Sensitive: true},
+					"info":                        schema.StringAttribute{Required: true},
+					"enabled":                     schema.BoolAttribute{Required: true},
+					"expire":                      schema.Int64Attribute{Optional: true, Computed: true},
+					"ttl":                         schema.Int64Attribute{Optional: true, Computed: true},
+					"account_id":                  schema.Int64Attribute{Optional: true, Computed: true},
+					"metadata":                    schema.MapAttribute{Optional: true, Computed: true, ElementType: types.StringType},
+					"preserve_unmanaged_metadata": schema.BoolAttribute{Optional: true, Computed: true},
+					"access":                      schema.StringAttribute{Optional: true, Computed: true, CustomType: jsontypes.NormalizedType{}},
+				},
+			},
+			StateUpgrader: func(ctx context.Context, request resource.UpgradeStateRequest, response *resource.UpgradeStateResponse) {
+				var prior tokenResourceModelV0
+
+				response.Diagnostics.Append(request.State.Get(ctx, &prior)...)
+
+				if response.Diagnostics.HasError() {
+					return
+				}
+
+				access, diags := tokenAccessValue(ctx, nil)
+
+				if !prior.Access.IsNull() && !prior.Access.IsUnknown() {
+					access, diags = tokenAccessFromJSON(ctx, prior.Access.ValueString())
+				}
+
+				response.Diagnostics.Append(diags...)
+
+				if response.Diagnostics.HasError() {
+					return
+				}
+
+				response.Diagnostics.Append(response.State.Set(ctx, tokenResourceModel{
+					Id:        prior.Id,
+					Key:       prior.Key,
+					Info:      prior.Info,
+					Enabled:   prior.Enabled,
+					Expire:    prior.Expire,
+					TTL:       prior.TTL,
+					AccountId: prior.AccountId,
+					Metadata:  prior.Metadata,
+					Access:    access,
+
+					AccessJSON: jsontypes.NewNormalizedNull(),
+
+					PreserveUnmanagedMetadata: prior.PreserveUnmanagedMetadata,
+				})...)
+			},
+		},
+	}
+}
+
+func (p *platformTokenResource) convertFlespiTokenToResourceModel(ctx context.Context, token *flespi_token.Token, managed types.Map, preserveUnmanaged types.Bool) (*tokenResourceModel, diag.Diagnostics) {
 	var result tokenResourceModel
 	var diags diag.Diagnostics
 
@@ -281,29 +493,19 @@ func (p *platformTokenResource) convertFlespiTokenToResourceModel(token *flespi_
 	result.TTL = types.Int64Value(token.TTL)
 	result.AccountId = types.Int64Value(token.AccountId)
 
-	metadata := make(map[string]attr.Value)
-	for key, value := range token.Metadata {
-		metadata[key] = types.StringValue(value)
-	}
-	meta, metaDiags := types.MapValue(types.StringType, metadata)
+	meta, metaDiags := common.MetadataValue(ctx, token.Metadata, managed, preserveUnmanaged.ValueBool())
 	diags.Append(metaDiags...)
 	result.Metadata = meta
+	result.PreserveUnmanagedMetadata = types.BoolValue(preserveUnmanaged.ValueBool())
 
-	if token.Access != nil {
-		b, err := json.Marshal(token.Access)
-		if err != nil {
-			diags.AddError("Failed to serialize token access", err.Error())
-		} else {
-			result.Access = jsontypes.NewNormalizedValue(string(b))
-		}
-	} else {
-		result.Access = jsontypes.NewNormalizedNull()
-	}
+	access, accessDiags := tokenAccessValue(ctx, token.Access)
+	diags.Append(accessDiags...)
+	result.Access = access
 
 	return &result, diags
 }
 
-func (p *platformTokenResource) convertResourceModelToFlespiToken(data tokenResourceModel) (flespi_token.Token, diag.Diagnostics) {
+func (p *platformTokenResource) convertResourceModelToFlespiToken(ctx context.Context, data tokenResourceModel) (flespi_token.Token, diag.Diagnostics) {
 	var diags diag.Diagnostics
 	metadata := make(map[string]string)
 
@@ -326,14 +528,9 @@ func (p *platformTokenResource) convertResourceModelToFlespiToken(data tokenReso
 		Metadata:  metadata,
 	}
 
-	if !data.Access.IsNull() && !data.Access.IsUnknown() {
-		var access flespi_token.TokenAccess
-		if err := json.Unmarshal([]byte(data.Access.ValueString()), &access); err != nil {
-			diags.AddError("Invalid access JSON", err.Error())
-		} else {
-			token.Access = &access
-		}
-	}
+	access, accessDiags := data.access(ctx)
+	diags.Append(accessDiags...)
+	token.Access = access
 
 	return token, diags
 }
@@ -0,0 +1,424 @@
+package platform
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	schemavalidator "github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	flespi_token "github.com/mixser/flespi-client/resources/gateway/token"
+)
+
+// tokenAccessTypes maps the access type names used in configuration to the
+// numeric types of the flespi API.
+var tokenAccessTypes = map[string]flespi_token.AccessType{
+	"standard": flespi_token.AccessTypeStandard,
+	"master":   flespi_token.AccessTypeMaster,
+	"acl":      flespi_token.AccessTypeACL,
+}
+
+var tokenACLURIs = []string{
+	flespi_token.ACEURIGwChannels,
+	flespi_token.ACEURIGwDevices,
+	flespi_token.ACEURIGwGroups,
+	flespi_token.ACEURIGwStreams,
+	flespi_token.ACEURIGwModems,
+	flespi_token.ACEURIGwCalcs,
+	flespi_token.ACEURIGwPlugins,
+	flespi_token.ACEURIGwGeofences,
+	flespi_token.ACEURIGwAssets,
+	flespi_token.ACEURIStorageContainers,
+	flespi_token.ACEURIStorageCDNs,
+	flespi_token.ACEURIMqtt,
+	flespi_token.ACEURIAI,
+}
+
+var tokenACLSubmodules = []string{
+	flespi_token.SubmoduleLogs,
+	flespi_token.SubmoduleMessages,
+	flespi_token.SubmoduleTelemetry,
+	flespi_token.SubmoduleSettings,
+	flespi_token.SubmoduleConnections,
+	flespi_token.SubmoduleDevices,
+	flespi_token.SubmoduleChannels,
+	flespi_token.SubmoduleGroups,
+	flespi_token.SubmoduleGeofences,
+	flespi_token.SubmoduleCalculate,
+	flespi_token.SubmoduleMedia,
+	flespi_token.SubmodulePackets,
+	flespi_token.SubmoduleCommands,
+	flespi_token.SubmoduleCommandsQueue,
+	flespi_token.SubmoduleCommandsResult,
+	flespi_token.SubmoduleSMS,
+	flespi_token.SubmoduleFiles,
+	flespi_token.SubmoduleIntervals,
+	flespi_token.SubmoduleIdents,
+}
+
+var tokenACLMethods = []string{"GET", "POST", "PUT", "DELETE"}
+
+type tokenAccessModel struct {
+	Type types.String    `tfsdk:"type"`
+	ACL  []tokenACEModel `tfsdk:"acl"`
+}
+
+type tokenACEModel struct {
+	URI        types.String          `tfsdk:"uri"`
+	Methods    types.Set             `tfsdk:"methods"`
+	IDs        types.Set             `tfsdk:"ids"`
+	IDsScope   types.String          `tfsdk:"ids_scope"`
+	Submodules []tokenSubmoduleModel `tfsdk:"submodules"`
+	Topic      types.String          `tfsdk:"topic"`
+	Actions    types.Set             `tfsdk:"actions"`
+}
+
+type tokenSubmoduleModel struct {
+	Name    types.String `tfsdk:"name"`
+	Methods types.Set    `tfsdk:"methods"`
+}
+
+// aceJSON is an ACE as sent by flespi. flespi_token.ACEIDs hides which form
+// the ids took, so entries read from the API are decoded through it.
+type aceJSON struct {
+	URI        string                   `json:"uri"`
+	Methods    []string                 `json:"methods"`
+	IDs        json.RawMessage          `json:"ids"`
+	Submodules []flespi_token.Submodule `json:"submodules"`
+	Topic      string                   `json:"topic"`
+	Actions    []string                 `json:"actions"`
+}
+
+func tokenAccessAttribute() schema.SingleNestedAttribute {
+	methods := func(description string) schema.SetAttribute {
+		return schema.SetAttribute{
+			ElementType: types.StringType,
+			Optional:    true,
+			Description: description,
+			Validators: []schemavalidator.Set{
+				setvalidator.ValueStringsAre(stringvalidator.OneOf(tokenACLMethods...)),
+			},
+		}
+	}
+
+	return schema.SingleNestedAttribute{
+		Optional:    true,
+		Computed:    true,
+		Description: "Token access permissions",
+		PlanModifiers: []planmodifier.Object{
+			objectplanmodifier.UseStateForUnknown(),
+		},
+		Attributes: map[string]schema.Attribute{
+			"type": schema.StringAttribute{
+				Required:    true,
+				Description: "Access type: standard, master or acl",
+				Validators: []schemavalidator.String{
+					stringvalidator.OneOf("standard", "master", "acl"),
+				},
+			},
+			"acl": schema.SetNestedAttribute{
+				Optional:    true,
+				Description: "Access control entries, required for the acl type",
+				Validators: []schemavalidator.Set{
+					setvalidator.SizeAtLeast(1),
+				},
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"uri": schema.StringAttribute{
+							Required:    true,
+							Description: "API the entry grants access to, e.g. gw/devices",
+							Validators: []schemavalidator.String{
+								stringvalidator.OneOf(tokenACLURIs...),
+							},
+						},
+						"methods": methods("HTTP methods allowed: GET, POST, PUT or DELETE"),
+						"ids": schema.SetAttribute{
+							ElementType: types.Int64Type,
+							Optional:    true,
+							Description: "IDs of the items the entry is limited to",
+							Validators: []schemavalidator.Set{
+								setvalidator.SizeAtLeast(1),
+							},
+						},
+						"ids_scope": schema.StringAttribute{
+							Optional:    true,
+							Description: "Items the entry applies to instead of a list of ids: all or in-groups",
+							Validators: []schemavalidator.String{
+								stringvalidator.OneOf("all", "in-groups"),
+								stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("ids")),
+							},
+						},
+						"submodules": schema.SetNestedAttribute{
+							Optional:    true,
+							Description: "Submodules the entry is limited to",
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: map[string]schema.Attribute{
+									"name": schema.StringAttribute{
+										Required:    true,
+										Description: "Submodule name, e.g. messages",
+										Validators: []schemavalidator.String{
+											stringvalidator.OneOf(tokenACLSubmodules...),
+										},
+									},
+									"methods": methods("HTTP methods allowed on the submodule"),
+								},
+							},
+						},
+						"topic": schema.StringAttribute{
+							Optional:    true,
+							Description: "MQTT topic filter, for the mqtt uri only",
+						},
+						"actions": schema.SetAttribute{
+							ElementType: types.StringType,
+							Optional:    true,
+							Description: "MQTT actions, for the mqtt uri only",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// validate reports the combinations the attribute validators can't see: an
+// acl list that doesn't match the type, and fields that don't apply to the
+// entry's uri.
+func (access tokenAccessModel) validate(diags *diag.Diagnostics) {
+	root := path.Root("access")
+
+	if !access.Type.IsUnknown() && !access.Type.IsNull() {
+		switch {
+		case access.Type.ValueString() == "acl" && len(access.ACL) == 0:
+			diags.AddAttributeError(root.AtName("acl"), "Missing Token ACL", "An access of type \"acl\" needs at least one acl entry.")
+		case access.Type.ValueString() != "acl" && len(access.ACL) > 0:
+			diags.AddAttributeError(root.AtName("acl"), "Unexpected Token ACL", fmt.Sprintf("acl entries only apply to the \"acl\" access type, not %q.", access.Type.ValueString()))
+		}
+	}
+
+	for _, ace := range access.ACL {
+		if ace.URI.IsUnknown() || ace.URI.IsNull() {
+			continue
+		}
+
+		uri := ace.URI.ValueString()
+
+		if uri == flespi_token.ACEURIMqtt {
+			if !ace.IDs.IsNull() || !ace.IDsScope.IsNull() || len(ace.Submodules) > 0 {
+				diags.AddAttributeError(root.AtName("acl"), "Invalid Token ACL Entry", "The mqtt entry takes topic and actions, not ids, ids_scope or submodules.")
+			}
+
+			if ace.Topic.IsNull() {
+				diags.AddAttributeError(root.AtName("acl"), "Invalid Token ACL Entry", "The mqtt entry needs a topic.")
+			}
+
+			continue
+		}
+
+		if !ace.Topic.IsNull() || !ace.Actions.IsNull() {
+			diags.AddAttributeError(root.AtName("acl"), "Invalid Token ACL Entry", fmt.Sprintf("topic and actions only apply to the mqtt uri, not %q.", uri))
+		}
+	}
+}
+
+// toFlespi converts a planned access into the library representation.
+func (access tokenAccessModel) toFlespi(ctx context.Context) (flespi_token.TokenAccess, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	result := flespi_token.TokenAccess{Type: tokenAccessTypes[access.Type.ValueString()]}
+
+	for _, ace := range access.ACL {
+		entry := flespi_token.ACE{
+			URI:   ace.URI.ValueString(),
+			Topic: ace.Topic.ValueString(),
+		}
+
+		diags.Append(stringsFromSet(ctx, ace.Methods, &entry.Methods)...)
+		diags.Append(stringsFromSet(ctx, ace.Actions, &entry.Actions)...)
+
+		switch {
+		case !ace.IDsScope.IsNull() && ace.IDsScope.ValueString() == "all":
+			entry.IDs = &flespi_token.ACEIDsAll
+		case !ace.IDsScope.IsNull() && ace.IDsScope.ValueString() == "in-groups":
+			entry.IDs = &flespi_token.ACEIDsInGroups
+		case !ace.IDs.IsNull():
+			var ids []int64
+
+			diags.Append(ace.IDs.ElementsAs(ctx, &ids, false)...)
+			sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+			list := flespi_token.ACEIDsList(ids...)
+			entry.IDs = &list
+		}
+
+		for _, submodule := range ace.Submodules {
+			converted := flespi_token.Submodule{Name: submodule.Name.ValueString()}
+
+			diags.Append(stringsFromSet(ctx, submodule.Methods, &converted.Methods)...)
+			entry.Submodules = append(entry.Submodules, converted)
+		}
+
+		result.ACL = append(result.ACL, entry)
+	}
+
+	return result, diags
+}
+
+// tokenAccessToFlespi converts the planned access value, or returns nil when
+// it is left to flespi.
+func tokenAccessToFlespi(ctx context.Context, value types.Object) (*flespi_token.TokenAccess, diag.Diagnostics) {
+	if value.IsNull() || value.IsUnknown() {
+		return nil, nil
+	}
+
+	var access tokenAccessModel
+
+	diags := value.As(ctx, &access, basetypes.ObjectAsOptions{})
+
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	result, convertDiags := access.toFlespi(ctx)
+	diags.Append(convertDiags...)
+
+	return &result, diags
+}
+
+// tokenAccessValue converts the access read from flespi into its attribute
+// value. The ACL and every list inside it become sets, so the order flespi
+// returns them in never shows up as a diff.
+func tokenAccessValue(ctx context.Context, access *flespi_token.TokenAccess) (types.Object, diag.Diagnostics) {
+	attributeTypes := tokenAccessAttribute().GetType().(types.ObjectType).AttrTypes
+
+	if access == nil {
+		return types.ObjectNull(attributeTypes), nil
+	}
+
+	model, diags := tokenAccessFromFlespi(ctx, *access)
+
+	if diags.HasError() {
+		return types.ObjectNull(attributeTypes), diags
+	}
+
+	value, valueDiags := types.ObjectValueFrom(ctx, attributeTypes, model)
+	diags.Append(valueDiags...)
+
+	return value, diags
+}
+
+func tokenAccessFromFlespi(ctx context.Context, access flespi_token.TokenAccess) (tokenAccessModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	result := tokenAccessModel{Type: types.StringValue(fmt.Sprintf("%d", access.Type))}
+
+	for name, accessType := range tokenAccessTypes {
+		if accessType == access.Type {
+			result.Type = types.StringValue(name)
+		}
+	}
+
+	encoded, err := json.Marshal(access.ACL)
+
+	if err != nil {
+		diags.AddError("Failed to read token access", err.Error())
+		return tokenAccessModel{}, diags
+	}
+
+	var entries []aceJSON
+
+	if err := json.Unmarshal(encoded, &entries); err != nil {
+		diags.AddError("Failed to read token access", err.Error())
+		return tokenAccessModel{}, diags
+	}
+
+	for _, entry := range entries {
+		ace := tokenACEModel{
+			URI:      types.StringValue(entry.URI),
+			Methods:  stringSetValue(entry.Methods),
+			IDs:      types.SetNull(types.Int64Type),
+			IDsScope: types.StringNull(),
+			Topic:    types.StringNull(),
+			Actions:  stringSetValue(entry.Actions),
+		}
+
+		if entry.Topic != "" {
+			ace.Topic = types.StringValue(entry.Topic)
+		}
+
+		var scope string
+		var ids []int64
+
+		switch {
+		case len(entry.IDs) == 0 || string(entry.IDs) == "null":
+		case json.Unmarshal(entry.IDs, &scope) == nil:
+			ace.IDsScope = types.StringValue(scope)
+		case json.Unmarshal(entry.IDs, &ids) == nil && len(ids) > 0:
+			value, setDiags := types.SetValueFrom(ctx, types.Int64Type, ids)
+			diags.Append(setDiags...)
+			ace.IDs = value
+		}
+
+		for _, submodule := range entry.Submodules {
+			ace.Submodules = append(ace.Submodules, tokenSubmoduleModel{
+				Name:    types.StringValue(submodule.Name),
+				Methods: stringSetValue(submodule.Methods),
+			})
+		}
+
+		result.ACL = append(result.ACL, ace)
+	}
+
+	return result, diags
+}
+
+// tokenAccessFromJSON converts access stored as JSON by schema version 0.
+func tokenAccessFromJSON(ctx context.Context, value string) (types.Object, diag.Diagnostics) {
+	var access flespi_token.TokenAccess
+
+	if err := json.Unmarshal([]byte(value), &access); err != nil {
+		var diags diag.Diagnostics
+		diags.AddError("Invalid access JSON", err.Error())
+		return types.ObjectNull(tokenAccessAttribute().GetType().(types.ObjectType).AttrTypes), diags
+	}
+
+	return tokenAccessValue(ctx, &access)
+}
+
+func stringsFromSet(ctx context.Context, value types.Set, target *[]string) diag.Diagnostics {
+	if value.IsNull() || value.IsUnknown() {
+		return nil
+	}
+
+	diags := value.ElementsAs(ctx, target, false)
+	sort.Strings(*target)
+
+	return diags
+}
+
+func stringSetValue(values []string) types.Set {
+	if len(values) == 0 {
+		return types.SetNull(types.StringType)
+	}
+
+	elements := make([]attr.Value, 0, len(values))
+	seen := make(map[string]bool, len(values))
+
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			elements = append(elements, types.StringValue(value))
+		}
+	}
+
+	return types.SetValueMust(types.StringType, elements)
+}
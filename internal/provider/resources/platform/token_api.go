@@ -0,0 +1,68 @@
+package platform
+
+import (
+	"fmt"
+	"terraform-provider-flespi/internal/provider/resources/common"
+
+	flespi "github.com/mixser/flespi-client"
+	flespi_token "github.com/mixser/flespi-client/resources/gateway/token"
+)
+
+type tokensResponse struct {
+	Tokens []flespi_token.Token `json:"result"`
+}
+
+// getToken reads a token like flespi_token.GetToken does, but reports an
+// empty result as a 404 instead of indexing past the end of it.
+func getToken(client *flespi.Client, tokenId int64) (*flespi_token.Token, error) {
+	response := tokensResponse{}
+
+	endpoint := fmt.Sprintf("platform/tokens/%d", tokenId)
+
+	if err := client.RequestAPI("GET", endpoint, nil, &response); err != nil {
+		return nil, err
+	}
+
+	if len(response.Tokens) == 0 {
+		return nil, &flespi.APIError{StatusCode: 404, Method: "GET", Endpoint: endpoint, Message: "token not found"}
+	}
+
+	return &response.Tokens[0], nil
+}
+
+// tokenUpdate is the body of a token update. Metadata is a pointer so that a
+// nil value leaves the stored metadata alone while an empty map clears it;
+// the client library's map is dropped from the request when it is empty.
+type tokenUpdate struct {
+	Info     string                    `json:"info"`
+	Enabled  bool                      `json:"enabled"`
+	Expire   int64                     `json:"expire"`
+	TTL      int64                     `json:"ttl"`
+	Metadata *map[string]string        `json:"metadata,omitempty"`
+	Access   *flespi_token.TokenAccess `json:"access,omitempty"`
+}
+
+func updateToken(client *flespi.Client, token flespi_token.Token, metadata *map[string]string) (*flespi_token.Token, error) {
+	headers := common.AccountHeaders(token.AccountId)
+
+	update := tokenUpdate{
+		Info:     token.Info,
+		Enabled:  token.Enabled,
+		Expire:   token.Expire,
+		TTL:      token.TTL,
+		Metadata: metadata,
+		Access:   token.Access,
+	}
+
+	response := tokensResponse{}
+
+	if err := client.RequestAPIWithHeaders("PUT", fmt.Sprintf("platform/tokens/%d", token.Id), headers, update, &response); err != nil {
+		return nil, err
+	}
+
+	if len(response.Tokens) == 0 {
+		return nil, fmt.Errorf("flespi returned no token")
+	}
+
+	return &response.Tokens[0], nil
+}
@@ -0,0 +1,331 @@
+package platform
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-flespi/internal/provider/resources/common"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	flespi "github.com/mixser/flespi-client"
+	flespi_token "github.com/mixser/flespi-client/resources/gateway/token"
+)
+
+var (
+	_ datasource.DataSource                     = &platformTokenDataSource{}
+	_ datasource.DataSourceWithConfigure        = &platformTokenDataSource{}
+	_ datasource.DataSourceWithConfigValidators = &platformTokenDataSource{}
+	_ datasource.DataSource                     = &platformTokensDataSource{}
+	_ datasource.DataSourceWithConfigure        = &platformTokensDataSource{}
+)
+
+type platformTokenDataSource struct {
+	provider *flespi.Client
+}
+
+type platformTokensDataSource struct {
+	provider *flespi.Client
+}
+
+type tokenDataSourceModel struct {
+	Id        types.Int64  `tfsdk:"id"`
+	Info      types.String `tfsdk:"info"`
+	Enabled   types.Bool   `tfsdk:"enabled"`
+	Expire    types.Int64  `tfsdk:"expire"`
+	TTL       types.Int64  `tfsdk:"ttl"`
+	Access    types.Object `tfsdk:"access"`
+	Metadata  types.Map    `tfsdk:"metadata"`
+	AccountId types.Int64  `tfsdk:"account_id"`
+}
+
+type tokensDataSourceModel struct {
+	NameRegex     types.String           `tfsdk:"name_regex"`
+	AccountId     types.Int64            `tfsdk:"account_id"`
+	Enabled       types.Bool             `tfsdk:"enabled"`
+	MetadataKey   types.String           `tfsdk:"metadata_key"`
+	MetadataValue types.String           `tfsdk:"metadata_value"`
+	Tokens        []tokenDataSourceModel `tfsdk:"tokens"`
+}
+
+func NewTokenDataSource() datasource.DataSource {
+	return &platformTokenDataSource{}
+}
+
+func NewTokensDataSource() datasource.DataSource {
+	return &platformTokensDataSource{}
+}
+
+func tokenDataSourceAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"id": schema.Int64Attribute{
+			Computed: true,
+		},
+		"info": schema.StringAttribute{
+			Computed: true,
+		},
+		"enabled": schema.BoolAttribute{
+			Computed: true,
+		},
+		"expire": schema.Int64Attribute{
+			Computed: true,
+		},
+		"ttl": schema.Int64Attribute{
+			Computed: true,
+		},
+		"access": schema.ObjectAttribute{
+			Computed:       true,
+			AttributeTypes: tokenAccessAttribute().GetType().(types.ObjectType).AttrTypes,
+			Description:    "Token access permissions, in the form of the access attribute of flespi_token",
+		},
+		"metadata": schema.MapAttribute{
+			Computed:    true,
+			ElementType: types.StringType,
+		},
+		"account_id": schema.Int64Attribute{
+			Computed: true,
+		},
+	}
+}
+
+func (d *platformTokenDataSource) Metadata(ctx context.Context, request datasource.MetadataRequest, response *datasource.MetadataResponse) {
+	response.TypeName = request.ProviderTypeName + "_token"
+}
+
+func (d *platformTokenDataSource) Configure(ctx context.Context, request datasource.ConfigureRequest, response *datasource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	client, ok := request.ProviderData.(*flespi.Client)
+
+	if !ok {
+		response.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *flespi.Client, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+		)
+
+		return
+	}
+
+	d.provider = client
+}
+
+// clientFor returns a token client acting as accountId, or as the provider account when it is null.
+func (d *platformTokenDataSource) clientFor(accountId types.Int64) *flespi_token.TokenClient {
+	return common.ForAccount(d.provider, accountId.ValueInt64()).Tokens
+}
+
+func (d *platformTokenDataSource) Schema(ctx context.Context, request datasource.SchemaRequest, response *datasource.SchemaResponse) {
+	attributes := tokenDataSourceAttributes()
+
+	attributes["id"] = schema.Int64Attribute{
+		Optional:    true,
+		Computed:    true,
+		Description: "ID of the token to look up.",
+	}
+	attributes["info"] = schema.StringAttribute{
+		Optional:    true,
+		Computed:    true,
+		Description: "Exact info of the token to look up.",
+	}
+
+	attributes["account_id"] = schema.Int64Attribute{
+		Optional:    true,
+		Computed:    true,
+		Description: "(Sub)account to look the token up in. Defaults to the provider's account_id.",
+	}
+
+	response.Schema = schema.Schema{
+		Description: "Looks up a single flespi token by id or exact info.",
+		Attributes:  attributes,
+	}
+}
+
+func (d *platformTokenDataSource) ConfigValidators(ctx context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		datasourcevalidator.ExactlyOneOf(
+			path.MatchRoot("id"),
+			path.MatchRoot("info"),
+		),
+	}
+}
+
+func (d *platformTokenDataSource) Read(ctx context.Context, request datasource.ReadRequest, response *datasource.ReadResponse) {
+	var config tokenDataSourceModel
+
+	response.Diagnostics.Append(request.Config.Get(ctx, &config)...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	client := d.clientFor(config.AccountId)
+
+	var token *flespi_token.Token
+	var err error
+
+	if !config.Id.IsNull() {
+		token, err = getToken(common.ForAccount(d.provider, config.AccountId.ValueInt64()), config.Id.ValueInt64())
+	} else {
+		var tokens []flespi_token.Token
+
+		tokens, err = client.List()
+
+		if err == nil {
+			token, err = common.FindByName(tokens, config.Info.ValueString(),
+				func(item flespi_token.Token) string { return item.Info },
+				func(item flespi_token.Token) int64 { return item.Id },
+			)
+		}
+	}
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error Reading Flespi Token",
+			"Could not look up Flespi token: "+err.Error(),
+		)
+		return
+	}
+
+	model, diags := convertFlespiTokenToDataSourceModel(ctx, token)
+
+	response.Diagnostics.Append(diags...)
+	response.Diagnostics.Append(response.State.Set(ctx, model)...)
+}
+
+func (d *platformTokensDataSource) Metadata(ctx context.Context, request datasource.MetadataRequest, response *datasource.MetadataResponse) {
+	response.TypeName = request.ProviderTypeName + "_tokens"
+}
+
+func (d *platformTokensDataSource) Configure(ctx context.Context, request datasource.ConfigureRequest, response *datasource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	client, ok := request.ProviderData.(*flespi.Client)
+
+	if !ok {
+		response.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *flespi.Client, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+		)
+
+		return
+	}
+
+	d.provider = client
+}
+
+// clientFor returns a token client acting as accountId, or as the provider account when it is null.
+func (d *platformTokensDataSource) clientFor(accountId types.Int64) *flespi_token.TokenClient {
+	return common.ForAccount(d.provider, accountId.ValueInt64()).Tokens
+}
+
+func (d *platformTokensDataSource) Schema(ctx context.Context, request datasource.SchemaRequest, response *datasource.SchemaResponse) {
+	response.Schema = schema.Schema{
+		Description: "Lists flespi tokens matching all of the given filters.",
+		Attributes: map[string]schema.Attribute{
+			"name_regex": schema.StringAttribute{
+				Optional:    true,
+				Description: "Regular expression the token info must match.",
+			},
+			"account_id": schema.Int64Attribute{
+				Optional:    true,
+				Description: "List tokens as this (sub)account and only return the ones it owns.",
+			},
+			"enabled": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Only return enabled (true) or disabled (false) tokens.",
+			},
+			"metadata_key": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only return tokens having this metadata key.",
+			},
+			"metadata_value": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only return tokens whose metadata_key has this value.",
+			},
+			"tokens": schema.ListNestedAttribute{
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: tokenDataSourceAttributes(),
+				},
+			},
+		},
+	}
+}
+
+func (d *platformTokensDataSource) Read(ctx context.Context, request datasource.ReadRequest, response *datasource.ReadResponse) {
+	var config tokensDataSourceModel
+
+	response.Diagnostics.Append(request.Config.Get(ctx, &config)...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	filter, diags := common.NewListFilter(config.NameRegex, config.AccountId, config.Enabled, config.MetadataKey, config.MetadataValue)
+
+	response.Diagnostics.Append(diags...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	tokens, err := d.clientFor(config.AccountId).List()
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error Reading Flespi Tokens",
+			"Could not list Flespi tokens: "+err.Error(),
+		)
+		return
+	}
+
+	config.Tokens = []tokenDataSourceModel{}
+
+	for _, token := range tokens {
+		if !filter.MatchName(token.Info) || !filter.MatchAccountId(token.AccountId) ||
+			!filter.MatchEnabled(token.Enabled) || !filter.MatchMetadata(token.Metadata) {
+			continue
+		}
+
+		model, diags := convertFlespiTokenToDataSourceModel(ctx, &token)
+
+		response.Diagnostics.Append(diags...)
+
+		if response.Diagnostics.HasError() {
+			return
+		}
+
+		config.Tokens = append(config.Tokens, *model)
+	}
+
+	response.Diagnostics.Append(response.State.Set(ctx, &config)...)
+}
+
+func convertFlespiTokenToDataSourceModel(ctx context.Context, token *flespi_token.Token) (*tokenDataSourceModel, diag.Diagnostics) {
+	access, diags := tokenAccessValue(ctx, token.Access)
+
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	metadata, metaDiags := types.MapValueFrom(ctx, types.StringType, token.Metadata)
+	diags.Append(metaDiags...)
+
+	return &tokenDataSourceModel{
+		Id:        types.Int64Value(token.Id),
+		Info:      types.StringValue(token.Info),
+		Enabled:   types.BoolValue(token.Enabled),
+		Expire:    types.Int64Value(token.Expire),
+		TTL:       types.Int64Value(token.TTL),
+		Access:    access,
+		Metadata:  metadata,
+		AccountId: types.Int64Value(token.AccountId),
+	}, diags
+}
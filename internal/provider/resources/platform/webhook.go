@@ -3,23 +3,27 @@ package platform
 import (
 	"context"
 	"fmt"
+	"terraform-provider-flespi/internal/provider/resources/common"
 
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	flespi "github.com/mixser/flespi-client"
 	flespi_webhook "github.com/mixser/flespi-client/resources/platform/webhook"
 )
 
 var (
-	_ resource.Resource              = &platformWebhookResource{}
-	_ resource.ResourceWithConfigure = &platformWebhookResource{}
+	_ resource.Resource                = &platformWebhookResource{}
+	_ resource.ResourceWithConfigure   = &platformWebhookResource{}
+	_ resource.ResourceWithImportState = &platformWebhookResource{}
 )
 
 type platformWebhookResource struct {
-	client *flespi_webhook.WebhookClient
+	client   *flespi_webhook.WebhookClient
+	provider *flespi.Client
 }
 
 type webhookResourceModel struct {
@@ -80,6 +84,7 @@ func (p *platformWebhookResource) Configure(ctx context.Context, request resourc
 	}
 
 	p.client = client.Webhooks
+	p.provider = client
 }
 
 func (p platformWebhookResource) Metadata(ctx context.Context, request resource.MetadataRequest, response *resource.MetadataResponse) {
@@ -229,7 +234,13 @@ func (p platformWebhookResource) Read(ctx context.Context, request resource.Read
 		return
 	}
 
-	webhook, err := p.client.Get(state.Id.ValueInt64())
+	webhook, err := getWebhook(p.provider, state.Id.ValueInt64())
+
+	if common.IsNotFound(err) {
+		tflog.Warn(ctx, "Flespi webhook not found, removing from state", map[string]interface{}{"id": state.Id.ValueInt64()})
+		response.State.RemoveResource(ctx)
+		return
+	}
 
 	if err != nil {
 		response.Diagnostics.AddError(
@@ -276,7 +287,7 @@ func (p platformWebhookResource) Update(ctx context.Context, request resource.Up
 		return
 	}
 
-	updatedWebhook, err := p.client.Get(webhookId)
+	updatedWebhook, err := getWebhook(p.provider, webhookId)
 
 	if err != nil {
 		response.Diagnostics.AddError(
@@ -306,7 +317,7 @@ func (p platformWebhookResource) Delete(ctx context.Context, request resource.De
 
 	err := p.client.DeleteById(state.Id.ValueInt64())
 
-	if err != nil {
+	if err != nil && !common.IsNotFound(err) {
 		response.Diagnostics.AddError(
 			"Error Deleting Flespi Webhook",
 			"Could not delete webhook, unexpected error: "+err.Error(),
@@ -315,6 +326,10 @@ func (p platformWebhookResource) Delete(ctx context.Context, request resource.De
 	}
 }
 
+func (p platformWebhookResource) ImportState(ctx context.Context, request resource.ImportStateRequest, response *resource.ImportStateResponse) {
+	common.ImportStateById(ctx, request, response)
+}
+
 func convertFlespiWebhookToResourceModel(webhook flespi_webhook.Webhook) *webhookResourceModel {
 	switch v := webhook.(type) {
 	case *flespi_webhook.SingleWebhook:
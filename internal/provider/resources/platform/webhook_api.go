@@ -0,0 +1,45 @@
+package platform
+
+import (
+	"encoding/json"
+	"fmt"
+
+	flespi "github.com/mixser/flespi-client"
+	flespi_webhook "github.com/mixser/flespi-client/resources/platform/webhook"
+)
+
+type webhooksResponse struct {
+	Webhooks []json.RawMessage `json:"result"`
+}
+
+// getWebhook reads a webhook like flespi_webhook.GetWebhook does, but reports
+// an empty result as a 404 instead of indexing past the end of it. The result
+// is decoded the same way the client library does it: as a single webhook
+// when it parses as one, and as a chained webhook otherwise.
+func getWebhook(client *flespi.Client, webhookId int64) (flespi_webhook.Webhook, error) {
+	response := webhooksResponse{}
+
+	endpoint := fmt.Sprintf("platform/webhooks/%d", webhookId)
+
+	if err := client.RequestAPI("GET", endpoint, nil, &response); err != nil {
+		return nil, err
+	}
+
+	if len(response.Webhooks) == 0 {
+		return nil, &flespi.APIError{StatusCode: 404, Method: "GET", Endpoint: endpoint, Message: "webhook not found"}
+	}
+
+	var single flespi_webhook.SingleWebhook
+
+	if err := json.Unmarshal(response.Webhooks[0], &single); err == nil {
+		return &single, nil
+	}
+
+	var chained flespi_webhook.ChainedWebhook
+
+	if err := json.Unmarshal(response.Webhooks[0], &chained); err != nil {
+		return nil, err
+	}
+
+	return &chained, nil
+}
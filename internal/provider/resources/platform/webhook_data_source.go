@@ -0,0 +1,286 @@
+package platform
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-flespi/internal/provider/resources/common"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	flespi "github.com/mixser/flespi-client"
+	flespi_webhook "github.com/mixser/flespi-client/resources/platform/webhook"
+)
+
+var (
+	_ datasource.DataSource                     = &platformWebhookDataSource{}
+	_ datasource.DataSourceWithConfigure        = &platformWebhookDataSource{}
+	_ datasource.DataSourceWithConfigValidators = &platformWebhookDataSource{}
+	_ datasource.DataSource                     = &platformWebhooksDataSource{}
+	_ datasource.DataSourceWithConfigure        = &platformWebhooksDataSource{}
+)
+
+type platformWebhookDataSource struct {
+	client   *flespi_webhook.WebhookClient
+	provider *flespi.Client
+}
+
+type platformWebhooksDataSource struct {
+	client *flespi_webhook.WebhookClient
+}
+
+type webhookDataSourceModel struct {
+	Id   types.Int64  `tfsdk:"id"`
+	Name types.String `tfsdk:"name"`
+	Type types.String `tfsdk:"type"`
+}
+
+type webhooksDataSourceModel struct {
+	NameRegex types.String             `tfsdk:"name_regex"`
+	Webhooks  []webhookDataSourceModel `tfsdk:"webhooks"`
+}
+
+func NewWebhookDataSource() datasource.DataSource {
+	return &platformWebhookDataSource{}
+}
+
+func NewWebhooksDataSource() datasource.DataSource {
+	return &platformWebhooksDataSource{}
+}
+
+func webhookDataSourceAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"id": schema.Int64Attribute{
+			Computed: true,
+		},
+		"name": schema.StringAttribute{
+			Computed: true,
+		},
+		"type": schema.StringAttribute{
+			Computed: true,
+		},
+	}
+}
+
+func (d *platformWebhookDataSource) Metadata(ctx context.Context, request datasource.MetadataRequest, response *datasource.MetadataResponse) {
+	response.TypeName = request.ProviderTypeName + "_webhook"
+}
+
+func (d *platformWebhookDataSource) Configure(ctx context.Context, request datasource.ConfigureRequest, response *datasource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	client, ok := request.ProviderData.(*flespi.Client)
+
+	if !ok {
+		response.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *flespi.Client, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client.Webhooks
+	d.provider = client
+}
+
+func (d *platformWebhookDataSource) Schema(ctx context.Context, request datasource.SchemaRequest, response *datasource.SchemaResponse) {
+	attributes := webhookDataSourceAttributes()
+
+	attributes["id"] = schema.Int64Attribute{
+		Optional:    true,
+		Computed:    true,
+		Description: "ID of the webhook to look up.",
+	}
+	attributes["name"] = schema.StringAttribute{
+		Optional:    true,
+		Computed:    true,
+		Description: "Exact name of the webhook to look up.",
+	}
+
+	response.Schema = schema.Schema{
+		Description: "Looks up a single flespi webhook by id or exact name.",
+		Attributes:  attributes,
+	}
+}
+
+func (d *platformWebhookDataSource) ConfigValidators(ctx context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		datasourcevalidator.ExactlyOneOf(
+			path.MatchRoot("id"),
+			path.MatchRoot("name"),
+		),
+	}
+}
+
+func (d *platformWebhookDataSource) Read(ctx context.Context, request datasource.ReadRequest, response *datasource.ReadResponse) {
+	var config webhookDataSourceModel
+
+	response.Diagnostics.Append(request.Config.Get(ctx, &config)...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	var webhook flespi_webhook.Webhook
+	var err error
+
+	if !config.Id.IsNull() {
+		webhook, err = getWebhook(d.provider, config.Id.ValueInt64())
+	} else {
+		var webhooks []flespi_webhook.Webhook
+
+		webhooks, err = d.client.List()
+
+		if err == nil {
+			var found *flespi_webhook.Webhook
+
+			found, err = common.FindByName(webhooks, config.Name.ValueString(),
+				func(item flespi_webhook.Webhook) string { return webhookName(item) },
+				func(item flespi_webhook.Webhook) int64 { return item.GetId() },
+			)
+
+			if err == nil {
+				webhook = *found
+			}
+		}
+	}
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error Reading Flespi Webhook",
+			"Could not look up Flespi webhook: "+err.Error(),
+		)
+		return
+	}
+
+	model, diags := convertFlespiWebhookToDataSourceModel(ctx, webhook)
+
+	response.Diagnostics.Append(diags...)
+	response.Diagnostics.Append(response.State.Set(ctx, model)...)
+}
+
+func (d *platformWebhooksDataSource) Metadata(ctx context.Context, request datasource.MetadataRequest, response *datasource.MetadataResponse) {
+	response.TypeName = request.ProviderTypeName + "_webhooks"
+}
+
+func (d *platformWebhooksDataSource) Configure(ctx context.Context, request datasource.ConfigureRequest, response *datasource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	client, ok := request.ProviderData.(*flespi.Client)
+
+	if !ok {
+		response.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *flespi.Client, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client.Webhooks
+}
+
+func (d *platformWebhooksDataSource) Schema(ctx context.Context, request datasource.SchemaRequest, response *datasource.SchemaResponse) {
+	response.Schema = schema.Schema{
+		Description: "Lists flespi webhooks matching all of the given filters.",
+		Attributes: map[string]schema.Attribute{
+			"name_regex": schema.StringAttribute{
+				Optional:    true,
+				Description: "Regular expression the webhook name must match.",
+			},
+			"webhooks": schema.ListNestedAttribute{
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: webhookDataSourceAttributes(),
+				},
+			},
+		},
+	}
+}
+
+func (d *platformWebhooksDataSource) Read(ctx context.Context, request datasource.ReadRequest, response *datasource.ReadResponse) {
+	var config webhooksDataSourceModel
+
+	response.Diagnostics.Append(request.Config.Get(ctx, &config)...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	filter, diags := common.NewListFilter(config.NameRegex, types.Int64Null(), types.BoolNull(), types.StringNull(), types.StringNull())
+
+	response.Diagnostics.Append(diags...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	webhooks, err := d.client.List()
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error Reading Flespi Webhooks",
+			"Could not list Flespi webhooks: "+err.Error(),
+		)
+		return
+	}
+
+	config.Webhooks = []webhookDataSourceModel{}
+
+	for _, webhook := range webhooks {
+		if !filter.MatchName(webhookName(webhook)) {
+			continue
+		}
+
+		model, diags := convertFlespiWebhookToDataSourceModel(ctx, webhook)
+
+		response.Diagnostics.Append(diags...)
+
+		if response.Diagnostics.HasError() {
+			return
+		}
+
+		config.Webhooks = append(config.Webhooks, *model)
+	}
+
+	response.Diagnostics.Append(response.State.Set(ctx, &config)...)
+}
+
+func convertFlespiWebhookToDataSourceModel(_ context.Context, webhook flespi_webhook.Webhook) (*webhookDataSourceModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	model := webhookDataSourceModel{
+		Id:   types.Int64Value(webhook.GetId()),
+		Name: types.StringValue(webhookName(webhook)),
+	}
+
+	switch webhook.(type) {
+	case *flespi_webhook.SingleWebhook:
+		model.Type = types.StringValue("single-webhook")
+	case *flespi_webhook.ChainedWebhook:
+		model.Type = types.StringValue("chained-webhook")
+	default:
+		diags.AddError("Unknown webhook type", fmt.Sprintf("Unexpected webhook type: %T", webhook))
+	}
+
+	return &model, diags
+}
+
+func webhookName(webhook flespi_webhook.Webhook) string {
+	switch wh := webhook.(type) {
+	case *flespi_webhook.SingleWebhook:
+		return wh.Name
+	case *flespi_webhook.ChainedWebhook:
+		return wh.Name
+	default:
+		return ""
+	}
+}
@@ -3,23 +3,27 @@ package storage
 import (
 	"context"
 	"fmt"
+	"terraform-provider-flespi/internal/provider/resources/common"
 
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	flespi "github.com/mixser/flespi-client"
 	flespi_cdn "github.com/mixser/flespi-client/resources/storage/cdn"
 )
 
 var (
-	_ resource.Resource              = &cdnResource{}
-	_ resource.ResourceWithConfigure = &cdnResource{}
+	_ resource.Resource                = &cdnResource{}
+	_ resource.ResourceWithConfigure   = &cdnResource{}
+	_ resource.ResourceWithImportState = &cdnResource{}
 )
 
 type cdnResource struct {
-	client *flespi_cdn.CDNClient
+	client   *flespi_cdn.CDNClient
+	provider *flespi.Client
 }
 
 type cdnResourceModel struct {
@@ -48,6 +52,7 @@ func (p *cdnResource) Configure(ctx context.Context, request resource.ConfigureR
 	}
 
 	p.client = client.CDNs
+	p.provider = client
 }
 
 func (p *cdnResource) Metadata(ctx context.Context, request resource.MetadataRequest, response *resource.MetadataResponse) {
@@ -113,7 +118,13 @@ func (p *cdnResource) Read(ctx context.Context, request resource.ReadRequest, re
 		return
 	}
 
-	cdn, err := p.client.Get(state.Id.ValueInt64())
+	cdn, err := getCDN(p.provider, state.Id.ValueInt64())
+
+	if common.IsNotFound(err) {
+		tflog.Warn(ctx, "Flespi CDN not found, removing from state", map[string]interface{}{"id": state.Id.ValueInt64()})
+		response.State.RemoveResource(ctx)
+		return
+	}
 
 	if err != nil {
 		response.Diagnostics.AddError(
@@ -157,7 +168,7 @@ func (p *cdnResource) Update(ctx context.Context, request resource.UpdateRequest
 		return
 	}
 
-	updatedCDN, err := p.client.Get(plan.Id.ValueInt64())
+	updatedCDN, err := getCDN(p.provider, plan.Id.ValueInt64())
 
 	if err != nil {
 		response.Diagnostics.AddError(
@@ -189,7 +200,7 @@ func (p *cdnResource) Delete(ctx context.Context, request resource.DeleteRequest
 
 	err := p.client.DeleteById(state.Id.ValueInt64())
 
-	if err != nil {
+	if err != nil && !common.IsNotFound(err) {
 		response.Diagnostics.AddError(
 			"Error Deleting Flespi CDN",
 			"Could not delete CDN, unexpected error: "+err.Error(),
@@ -198,6 +209,10 @@ func (p *cdnResource) Delete(ctx context.Context, request resource.DeleteRequest
 	}
 }
 
+func (p *cdnResource) ImportState(ctx context.Context, request resource.ImportStateRequest, response *resource.ImportStateResponse) {
+	common.ImportStateById(ctx, request, response)
+}
+
 func (p *cdnResource) convertFlespiCDNToResourceModel(cdn *flespi_cdn.CDN) *cdnResourceModel {
 	return &cdnResourceModel{
 		Id:      types.Int64Value(cdn.Id),
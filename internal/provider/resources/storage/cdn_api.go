@@ -0,0 +1,30 @@
+package storage
+
+import (
+	"fmt"
+
+	flespi "github.com/mixser/flespi-client"
+	flespi_cdn "github.com/mixser/flespi-client/resources/storage/cdn"
+)
+
+type cdnsResponse struct {
+	CDNs []flespi_cdn.CDN `json:"result"`
+}
+
+// getCDN reads a CDN like flespi_cdn.GetCDN does, but reports an empty result
+// as a 404 instead of indexing past the end of it.
+func getCDN(client *flespi.Client, cdnId int64) (*flespi_cdn.CDN, error) {
+	response := cdnsResponse{}
+
+	endpoint := fmt.Sprintf("storage/cdns/%d", cdnId)
+
+	if err := client.RequestAPI("GET", endpoint, nil, &response); err != nil {
+		return nil, err
+	}
+
+	if len(response.CDNs) == 0 {
+		return nil, &flespi.APIError{StatusCode: 404, Method: "GET", Endpoint: endpoint, Message: "CDN not found"}
+	}
+
+	return &response.CDNs[0], nil
+}
@@ -0,0 +1,262 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-flespi/internal/provider/resources/common"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	flespi "github.com/mixser/flespi-client"
+	flespi_cdn "github.com/mixser/flespi-client/resources/storage/cdn"
+)
+
+var (
+	_ datasource.DataSource                     = &cdnDataSource{}
+	_ datasource.DataSourceWithConfigure        = &cdnDataSource{}
+	_ datasource.DataSourceWithConfigValidators = &cdnDataSource{}
+	_ datasource.DataSource                     = &cdnsDataSource{}
+	_ datasource.DataSourceWithConfigure        = &cdnsDataSource{}
+)
+
+type cdnDataSource struct {
+	client   *flespi_cdn.CDNClient
+	provider *flespi.Client
+}
+
+type cdnsDataSource struct {
+	client *flespi_cdn.CDNClient
+}
+
+type cdnDataSourceModel struct {
+	Id      types.Int64  `tfsdk:"id"`
+	Name    types.String `tfsdk:"name"`
+	Size    types.Int64  `tfsdk:"size"`
+	Blocked types.Bool   `tfsdk:"blocked"`
+}
+
+type cdnsDataSourceModel struct {
+	NameRegex types.String         `tfsdk:"name_regex"`
+	CDNs      []cdnDataSourceModel `tfsdk:"cdns"`
+}
+
+func NewCDNDataSource() datasource.DataSource {
+	return &cdnDataSource{}
+}
+
+func NewCDNsDataSource() datasource.DataSource {
+	return &cdnsDataSource{}
+}
+
+func cdnDataSourceAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"id": schema.Int64Attribute{
+			Computed: true,
+		},
+		"name": schema.StringAttribute{
+			Computed: true,
+		},
+		"size": schema.Int64Attribute{
+			Computed: true,
+		},
+		"blocked": schema.BoolAttribute{
+			Computed: true,
+		},
+	}
+}
+
+func (d *cdnDataSource) Metadata(ctx context.Context, request datasource.MetadataRequest, response *datasource.MetadataResponse) {
+	response.TypeName = request.ProviderTypeName + "_cdn"
+}
+
+func (d *cdnDataSource) Configure(ctx context.Context, request datasource.ConfigureRequest, response *datasource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	client, ok := request.ProviderData.(*flespi.Client)
+
+	if !ok {
+		response.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *flespi.Client, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client.CDNs
+	d.provider = client
+}
+
+func (d *cdnDataSource) Schema(ctx context.Context, request datasource.SchemaRequest, response *datasource.SchemaResponse) {
+	attributes := cdnDataSourceAttributes()
+
+	attributes["id"] = schema.Int64Attribute{
+		Optional:    true,
+		Computed:    true,
+		Description: "ID of the CDN to look up.",
+	}
+	attributes["name"] = schema.StringAttribute{
+		Optional:    true,
+		Computed:    true,
+		Description: "Exact name of the CDN to look up.",
+	}
+
+	response.Schema = schema.Schema{
+		Description: "Looks up a single flespi CDN by id or exact name.",
+		Attributes:  attributes,
+	}
+}
+
+func (d *cdnDataSource) ConfigValidators(ctx context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		datasourcevalidator.ExactlyOneOf(
+			path.MatchRoot("id"),
+			path.MatchRoot("name"),
+		),
+	}
+}
+
+func (d *cdnDataSource) Read(ctx context.Context, request datasource.ReadRequest, response *datasource.ReadResponse) {
+	var config cdnDataSourceModel
+
+	response.Diagnostics.Append(request.Config.Get(ctx, &config)...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	var cdn *flespi_cdn.CDN
+	var err error
+
+	if !config.Id.IsNull() {
+		cdn, err = getCDN(d.provider, config.Id.ValueInt64())
+	} else {
+		var cdns []flespi_cdn.CDN
+
+		cdns, err = d.client.List()
+
+		if err == nil {
+			cdn, err = common.FindByName(cdns, config.Name.ValueString(),
+				func(item flespi_cdn.CDN) string { return item.Name },
+				func(item flespi_cdn.CDN) int64 { return item.Id },
+			)
+		}
+	}
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error Reading Flespi CDN",
+			"Could not look up Flespi CDN: "+err.Error(),
+		)
+		return
+	}
+
+	model, diags := convertFlespiCDNToDataSourceModel(ctx, cdn)
+
+	response.Diagnostics.Append(diags...)
+	response.Diagnostics.Append(response.State.Set(ctx, model)...)
+}
+
+func (d *cdnsDataSource) Metadata(ctx context.Context, request datasource.MetadataRequest, response *datasource.MetadataResponse) {
+	response.TypeName = request.ProviderTypeName + "_cdns"
+}
+
+func (d *cdnsDataSource) Configure(ctx context.Context, request datasource.ConfigureRequest, response *datasource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+
+	client, ok := request.ProviderData.(*flespi.Client)
+
+	if !ok {
+		response.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *flespi.Client, got: %T. Please report this issue to the provider developers.", request.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client.CDNs
+}
+
+func (d *cdnsDataSource) Schema(ctx context.Context, request datasource.SchemaRequest, response *datasource.SchemaResponse) {
+	response.Schema = schema.Schema{
+		Description: "Lists flespi CDNs matching all of the given filters.",
+		Attributes: map[string]schema.Attribute{
+			"name_regex": schema.StringAttribute{
+				Optional:    true,
+				Description: "Regular expression the CDN name must match.",
+			},
+			"cdns": schema.ListNestedAttribute{
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: cdnDataSourceAttributes(),
+				},
+			},
+		},
+	}
+}
+
+func (d *cdnsDataSource) Read(ctx context.Context, request datasource.ReadRequest, response *datasource.ReadResponse) {
+	var config cdnsDataSourceModel
+
+	response.Diagnostics.Append(request.Config.Get(ctx, &config)...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	filter, diags := common.NewListFilter(config.NameRegex, types.Int64Null(), types.BoolNull(), types.StringNull(), types.StringNull())
+
+	response.Diagnostics.Append(diags...)
+
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	cdns, err := d.client.List()
+
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Error Reading Flespi CDNs",
+			"Could not list Flespi CDNs: "+err.Error(),
+		)
+		return
+	}
+
+	config.CDNs = []cdnDataSourceModel{}
+
+	for _, cdn := range cdns {
+		if !filter.MatchName(cdn.Name) {
+			continue
+		}
+
+		model, diags := convertFlespiCDNToDataSourceModel(ctx, &cdn)
+
+		response.Diagnostics.Append(diags...)
+
+		if response.Diagnostics.HasError() {
+			return
+		}
+
+		config.CDNs = append(config.CDNs, *model)
+	}
+
+	response.Diagnostics.Append(response.State.Set(ctx, &config)...)
+}
+
+func convertFlespiCDNToDataSourceModel(_ context.Context, cdn *flespi_cdn.CDN) (*cdnDataSourceModel, diag.Diagnostics) {
+	return &cdnDataSourceModel{
+		Id:      types.Int64Value(cdn.Id),
+		Name:    types.StringValue(cdn.Name),
+		Size:    types.Int64Value(cdn.Size),
+		Blocked: types.BoolValue(cdn.Blocked),
+	}, nil
+}